@@ -0,0 +1,160 @@
+package ari
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// memoryConfig holds the configuration of the in-process bus.
+type memoryConfig struct {
+	Buffer int `json:"buffer"`
+}
+
+// CheckBusConfig validates the configuration of a message bus without
+// connecting to it. Unknown settings, values of the wrong type and missing or
+// malformed settings are all reported, joined in one error. Settings a bus
+// has a default for may be left out.
+func CheckBusConfig(busType string, config interface{}) error {
+	var errs []error
+	switch busType {
+	case "NATS":
+		var c natsConfig
+		errs = decodeBusConfig("nats", config, &c)
+		errs = append(errs, CheckURL("nats: url", c.URL, "nats", "tls"))
+	case "RABBITMQ":
+		var c rabbitmqConfig
+		errs = decodeBusConfig("rabbitmq", config, &c)
+		errs = append(errs, CheckURL("rabbitmq: url", c.URL, "amqp", "amqps"))
+	case "KAFKA":
+		var c kafkaConfig
+		errs = decodeBusConfig("kafka", config, &c)
+		if len(c.Brokers) == 0 {
+			errs = append(errs, errors.New("kafka: brokers is required"))
+		}
+		switch strings.ToLower(c.Acks) {
+		case "", "none", "0", "leader", "one", "1", "all", "-1":
+		default:
+			errs = append(errs, fmt.Errorf("kafka: invalid acks %q", c.Acks))
+		}
+		if c.Partitions < 0 || c.ReplicationFactor < 0 || c.BatchTimeout < 0 {
+			errs = append(errs, errors.New("kafka: partitions, replication_factor and batch_timeout_ms cannot be negative"))
+		}
+	case "REDIS":
+		var c redisConfig
+		errs = decodeBusConfig("redis", config, &c)
+		if c.URL != "" {
+			errs = append(errs, CheckURL("redis: url", c.URL, "redis", "rediss"))
+		}
+		if c.MaxLen < 0 || c.DialogTTL < 0 {
+			errs = append(errs, errors.New("redis: maxlen and dialog_ttl cannot be negative"))
+		}
+	case "MQTT":
+		var c mqttConfig
+		errs = decodeBusConfig("mqtt", config, &c)
+		if c.URL != "" {
+			errs = append(errs, CheckURL("mqtt: url", c.URL, "tcp", "ssl", "tls", "ws", "wss", "mqtt", "mqtts"))
+		}
+		if c.QoS > 2 {
+			errs = append(errs, fmt.Errorf("mqtt: invalid qos %d", c.QoS))
+		}
+	case "MEMORY":
+		var c memoryConfig
+		errs = decodeBusConfig("memory", config, &c)
+		if c.Buffer < 0 {
+			errs = append(errs, errors.New("memory: buffer cannot be negative"))
+		}
+	case "WEBHOOK":
+		var c webhookConfig
+		errs = decodeBusConfig("webhook", config, &c)
+		if len(c.Applications) == 0 {
+			errs = append(errs, errors.New("webhook: applications is required"))
+		}
+		if c.MaxRetries < 0 || c.RetryBackoff < 0 || c.CommandTimeout < 0 {
+			errs = append(errs, errors.New("webhook: max_retries, retry_backoff_ms and command_timeout_ms cannot be negative"))
+		}
+		for _, app := range SortedKeys(c.Applications) {
+			setting := fmt.Sprintf("webhook: application %s: url", app)
+			errs = append(errs, CheckURL(setting, c.Applications[app].URL, "http", "https"))
+		}
+	case "OSLO":
+		errs = append(errs, errors.New("OSLO message bus producer is not yet implemented"))
+	default:
+		errs = append(errs, fmt.Errorf("unknown message bus %q", busType))
+	}
+	// the URL checks add nil errors, which Join leaves out
+	return errors.Join(errs...)
+}
+
+// decodeBusConfig decodes the configuration of a bus into its configuration
+// struct, setting by setting, and reports every setting which is unknown or
+// of the wrong type.
+func decodeBusConfig(prefix string, config interface{}, v interface{}) []error {
+	if config == nil {
+		return nil
+	}
+	settings, ok := config.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("%s: bus_config must be an object", prefix)}
+	}
+	fields := make(map[string]reflect.Value)
+	s := reflect.ValueOf(v).Elem()
+	for i := 0; i < s.NumField(); i++ {
+		name := strings.Split(s.Type().Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = s.Field(i)
+	}
+
+	var errs []error
+	for _, key := range SortedKeys(settings) {
+		field, ok := fields[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown setting %q", prefix, key))
+			continue
+		}
+		j, _ := json.Marshal(settings[key])
+		d := json.NewDecoder(bytes.NewReader(j))
+		d.DisallowUnknownFields()
+		if err := d.Decode(field.Addr().Interface()); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				err = fmt.Errorf("got %s, want %s", typeErr.Value, typeErr.Type)
+			}
+			errs = append(errs, fmt.Errorf("%s: invalid %s: %s", prefix, key, strings.TrimPrefix(err.Error(), "json: ")))
+		}
+	}
+	return errs
+}
+
+// CheckURL reports a missing URL, or one which is malformed or has none of
+// the schemes a setting accepts. Errors start with the setting.
+func CheckURL(setting string, value string, schemes ...string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", setting)
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid URL %q", setting, value)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme && u.Host != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: invalid URL %q, want a %s:// URL", setting, value, strings.Join(schemes, ":// or "))
+}
+
+// SortedKeys returns the keys of a map in order, so that configuration
+// errors are reported in a stable order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package ari
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// joinedErrors returns the messages of the errors joined in err.
+func joinedErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []string{err.Error()}
+	}
+	var messages []string
+	for _, e := range joined.Unwrap() {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
+
+func TestCheckBusConfig(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		bus    string
+		config string // bus_config as read from the configuration file
+		want   []string
+	}{
+		{"valid", "NATS", `{"url": "nats://localhost:4222", "queue": "proxy"}`, nil},
+		{"defaults", "MEMORY", `null`, nil},
+		{"unknown bus", "CARRIER_PIGEON", `null`, []string{`unknown message bus "CARRIER_PIGEON"`}},
+		{"not an object", "NATS", `"nats://localhost:4222"`, []string{"nats: bus_config must be an object", "nats: url is required"}},
+		{"unknown setting", "MEMORY", `{"size": 8}`, []string{`memory: unknown setting "size"`}},
+		{"type error", "MEMORY", `{"buffer": "8"}`, []string{"memory: invalid buffer: got string, want int"}},
+		{"nested unknown setting", "MQTT", `{"tls": {"ca": "ca.pem"}}`,
+			[]string{`mqtt: invalid tls: unknown field "ca"`}},
+		{"missing url", "RABBITMQ", `{}`, []string{"rabbitmq: url is required"}},
+		{"wrong scheme", "REDIS", `{"url": "http://localhost:6379"}`,
+			[]string{`redis: url: invalid URL "http://localhost:6379", want a redis:// or rediss:// URL`}},
+		{"malformed url", "NATS", `{"url": "nats://local host:4222"}`, []string{`nats: url: invalid URL "nats://local host:4222"`}},
+		{"url without a host", "NATS", `{"url": "nats:localhost"}`,
+			[]string{`nats: url: invalid URL "nats:localhost", want a nats:// or tls:// URL`}},
+		{"every problem", "KAFKA", `{"acks": "some", "partitions": -1, "group": "proxy"}`, []string{
+			`kafka: unknown setting "group"`,
+			"kafka: brokers is required",
+			`kafka: invalid acks "some"`,
+			"kafka: partitions, replication_factor and batch_timeout_ms cannot be negative",
+		}},
+		{"webhook applications", "WEBHOOK", `{"applications": {"b": {"url": "ftp://b"}, "a": {"secret": "s"}}}`, []string{
+			"webhook: application a: url is required",
+			`webhook: application b: url: invalid URL "ftp://b", want a http:// or https:// URL`,
+		}},
+		{"mqtt qos", "MQTT", `{"qos": 3}`, []string{"mqtt: invalid qos 3"}},
+	} {
+		var config interface{}
+		if err := json.Unmarshal([]byte(test.config), &config); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if got := joinedErrors(CheckBusConfig(test.bus, config)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got errors %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, test := range []struct {
+		value string
+		want  string
+	}{
+		{"https://localhost:8088/ari", ""},
+		{"http://localhost", ""},
+		{"", "stasis_url is required"},
+		{"localhost:8088", `stasis_url: invalid URL "localhost:8088", want a http:// or https:// URL`},
+		{"ws://localhost:8088", `stasis_url: invalid URL "ws://localhost:8088", want a http:// or https:// URL`},
+		{"http://local\x7fhost", `stasis_url: invalid URL "http://local\x7fhost"`},
+	} {
+		err := CheckURL("stasis_url", test.value, "http", "https")
+		if got := joinedErrors(err); (test.want == "" && got != nil) || (test.want != "" && (len(got) != 1 || got[0] != test.want)) {
+			t.Errorf("%q: got %v, want %q", test.value, err, test.want)
+		}
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	if got := SortedKeys(map[string]int{"c": 3, "a": 1, "b": 2}); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("got %v, want [a b c]", got)
+	}
+}
@@ -0,0 +1,169 @@
+package ari
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"io/ioutil"
+)
+
+// DefaultDecompressionLimit is the largest body, in bytes, a payload is
+// decompressed to unless SetDecompressionLimit sets another.
+const DefaultDecompressionLimit = 16 << 20
+
+// global variables
+var compression string                             // algorithm used to compress large payloads, none when empty
+var compressionThreshold int                       // payloads of at least this many bytes are compressed
+var decompressionLimit = DefaultDecompressionLimit // largest body a payload is decompressed to
+var zstdEncoder *zstd.Encoder                      // shared, as EncodeAll is safe for concurrent use
+
+func init() {
+	zstdEncoder, _ = zstd.NewWriter(nil)
+}
+
+// SetCompression selects the algorithm, "gzip" or "zstd", with which the
+// ARI body of Events and the body of CommandResponses of at least threshold
+// bytes are compressed. The empty string disables compression. Receivers
+// decompress payloads whatever this setting.
+func SetCompression(algorithm string, threshold int) error {
+	if err := CheckCompression(algorithm, threshold); err != nil {
+		return err
+	}
+	compression = algorithm
+	compressionThreshold = threshold
+	return nil
+}
+
+// CheckCompression reports an algorithm or threshold SetCompression refuses,
+// without selecting them.
+func CheckCompression(algorithm string, threshold int) error {
+	switch algorithm {
+	case "", "gzip", "zstd":
+	default:
+		return fmt.Errorf("unknown compression %q", algorithm)
+	}
+	if threshold < 0 {
+		return fmt.Errorf("invalid compression threshold %d", threshold)
+	}
+	return nil
+}
+
+// SetDecompressionLimit sets the largest body, in bytes, a compressed payload
+// is decompressed to. Payloads that inflate to more are refused, so that a
+// small message cannot exhaust the memory of its receiver.
+func SetDecompressionLimit(limit int) error {
+	if limit <= 0 {
+		return fmt.Errorf("invalid decompression limit %d", limit)
+	}
+	decompressionLimit = limit
+	return nil
+}
+
+// Compress moves the ARI body of an Event into its compressed payload when
+// compression is enabled and the body reaches the threshold.
+func (e *Event) Compress() error {
+	payload, ok, err := compress(e.ARI_Body)
+	if !ok {
+		return err
+	}
+	e.Compression, e.Payload, e.ARI_Body = compression, payload, nil
+	return nil
+}
+
+// Decompress restores the ARI body of an Event from its compressed payload.
+func (e *Event) Decompress() error {
+	if e.Compression == "" {
+		return nil
+	}
+	body, err := decompress(e.Compression, e.Payload)
+	if err != nil {
+		return err
+	}
+	e.Compression, e.Payload, e.ARI_Body = "", nil, json.RawMessage(body)
+	return nil
+}
+
+// Compress moves the body of a CommandResponse into its compressed payload
+// when compression is enabled and the body reaches the threshold.
+func (r *CommandResponse) Compress() error {
+	payload, ok, err := compress([]byte(r.ResponseBody))
+	if !ok {
+		return err
+	}
+	r.Compression, r.Payload, r.ResponseBody = compression, payload, ""
+	return nil
+}
+
+// Decompress restores the body of a CommandResponse from its compressed
+// payload.
+func (r *CommandResponse) Decompress() error {
+	if r.Compression == "" {
+		return nil
+	}
+	body, err := decompress(r.Compression, r.Payload)
+	if err != nil {
+		return err
+	}
+	r.Compression, r.Payload, r.ResponseBody = "", nil, string(body)
+	return nil
+}
+
+// compress compresses a body with the selected algorithm. It reports false
+// when the body is to be sent as it is.
+func compress(body []byte) ([]byte, bool, error) {
+	if compression == "" || len(body) < compressionThreshold {
+		return nil, false, nil
+	}
+	switch compression {
+	case "zstd":
+		return zstdEncoder.EncodeAll(body, nil), true, nil
+	default:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(body); err != nil {
+			return nil, false, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, false, err
+		}
+		return buf.Bytes(), true, nil
+	}
+}
+
+// decompress decompresses a payload compressed with the given algorithm, up
+// to the decompression limit.
+func decompress(algorithm string, payload []byte) ([]byte, error) {
+	var r io.Reader
+	switch algorithm {
+	case "gzip":
+		g, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		defer g.Close()
+		r = g
+	case "zstd":
+		z, err := zstd.NewReader(bytes.NewReader(payload), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer z.Close()
+		r = z
+	default:
+		return nil, fmt.Errorf("unknown compression %q", algorithm)
+	}
+	// read one byte past the limit to tell a body of exactly the limit from
+	// a larger one
+	limit := decompressionLimit
+	body, err := ioutil.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > limit {
+		return nil, fmt.Errorf("%s payload decompresses to more than %d bytes", algorithm, limit)
+	}
+	return body, nil
+}
@@ -0,0 +1,37 @@
+package ari
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecompressionLimit(t *testing.T) {
+	defer SetCompression("", 0)
+	defer SetDecompressionLimit(DefaultDecompressionLimit)
+	if err := SetDecompressionLimit(0); err == nil {
+		t.Error("a decompression limit of 0 was accepted")
+	}
+	body := json.RawMessage(`"` + strings.Repeat("a", 1022) + `"`)
+	for _, algorithm := range []string{"gzip", "zstd"} {
+		if err := SetCompression(algorithm, 1); err != nil {
+			t.Fatal(err)
+		}
+		e := Event{Type: "ChannelsList", ARI_Body: body}
+		if err := e.Compress(); err != nil || e.Compression != algorithm {
+			t.Fatalf("%s: the event was not compressed: %v", algorithm, err)
+		}
+
+		// a body of exactly the limit is restored, a larger one refused
+		SetDecompressionLimit(len(body))
+		restored := e
+		if err := restored.Decompress(); err != nil || string(restored.ARI_Body) != string(body) {
+			t.Errorf("%s: a body of the limit decompressed to %d bytes: %v", algorithm, len(restored.ARI_Body), err)
+		}
+		SetDecompressionLimit(len(body) - 1)
+		refused := e
+		if err := refused.Decompress(); err == nil {
+			t.Errorf("%s: a body past the limit was decompressed", algorithm)
+		}
+	}
+}
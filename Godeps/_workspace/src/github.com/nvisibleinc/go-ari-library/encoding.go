@@ -0,0 +1,256 @@
+package ari
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/nvisibleinc/go-ari-library/envelopepb"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// global variables
+var encoding Encoding = JSONEncoding{} // global var that holds the encoding of bus messages.
+
+// cborMode encodes timestamps as RFC 3339 strings, as the default of Unix
+// seconds would drop their sub-second part.
+var cborMode, _ = cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+
+// Encoding marshals the messages exchanged over the message bus, Event,
+// AppStart, Command and CommandResponse, to and from their wire format.
+type Encoding interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONEncoding is the default encoding.
+type JSONEncoding struct{}
+
+// MsgpackEncoding encodes messages as MessagePack, with the JSON field names.
+type MsgpackEncoding struct{}
+
+// CBOREncoding encodes messages as CBOR, with the JSON field names.
+type CBOREncoding struct{}
+
+// ProtobufEncoding encodes messages as the protocol buffers defined in
+// envelopepb.
+type ProtobufEncoding struct{}
+
+// SetEncoding selects the encoding of bus messages by name: "json",
+// "protobuf", "msgpack" or "cbor". The empty string selects JSON. Proxies and
+// applications sharing a bus must use the same encoding.
+func SetEncoding(name string) error {
+	e, err := encodingNamed(name)
+	if err != nil {
+		return err
+	}
+	encoding = e
+	return nil
+}
+
+// CheckEncoding reports an encoding name SetEncoding does not know, without
+// selecting it.
+func CheckEncoding(name string) error {
+	_, err := encodingNamed(name)
+	return err
+}
+
+// encodingNamed returns the encoding of a name SetEncoding takes.
+func encodingNamed(name string) (Encoding, error) {
+	switch name {
+	case "", "json":
+		return JSONEncoding{}, nil
+	case "protobuf":
+		return ProtobufEncoding{}, nil
+	case "msgpack":
+		return MsgpackEncoding{}, nil
+	case "cbor":
+		return CBOREncoding{}, nil
+	}
+	return nil, fmt.Errorf("unknown encoding %q", name)
+}
+
+// ContentType returns the MIME type of the selected encoding, for buses that
+// carry it in message headers.
+func ContentType() string {
+	return encoding.ContentType()
+}
+
+// Marshal encodes a bus message with the selected encoding.
+func Marshal(v interface{}) ([]byte, error) {
+	return encoding.Marshal(v)
+}
+
+// Unmarshal decodes a bus message with the selected encoding.
+func Unmarshal(data []byte, v interface{}) error {
+	return encoding.Unmarshal(data, v)
+}
+
+func (JSONEncoding) ContentType() string {
+	return "application/json"
+}
+
+func (JSONEncoding) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONEncoding) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (MsgpackEncoding) ContentType() string {
+	return "application/msgpack"
+}
+
+func (MsgpackEncoding) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
+
+func (MsgpackEncoding) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+func (CBOREncoding) ContentType() string {
+	return "application/cbor"
+}
+
+func (CBOREncoding) Marshal(v interface{}) ([]byte, error) {
+	return cborMode.Marshal(v)
+}
+
+func (CBOREncoding) Unmarshal(data []byte, v interface{}) error {
+	return cbor.Unmarshal(data, v)
+}
+
+func (ProtobufEncoding) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (ProtobufEncoding) Marshal(v interface{}) ([]byte, error) {
+	var m proto.Message
+	switch e := v.(type) {
+	case Event:
+		m = eventToProto(&e)
+	case *Event:
+		m = eventToProto(e)
+	case AppStart:
+		m = &envelopepb.AppStart{Application: e.Application, DialogId: e.DialogID, ServerId: e.ServerID}
+	case *AppStart:
+		m = &envelopepb.AppStart{Application: e.Application, DialogId: e.DialogID, ServerId: e.ServerID}
+	case Command:
+		m = &envelopepb.Command{UniqueId: e.UniqueID, Url: e.URL, Method: e.Method, Body: e.Body, Envelope: envelopeToProto(&e.Envelope), Signature: e.Signature}
+	case *Command:
+		m = &envelopepb.Command{UniqueId: e.UniqueID, Url: e.URL, Method: e.Method, Body: e.Body, Envelope: envelopeToProto(&e.Envelope), Signature: e.Signature}
+	case CommandResponse:
+		m = &envelopepb.CommandResponse{UniqueId: e.UniqueID, StatusCode: int32(e.StatusCode), ResponseBody: e.ResponseBody, Envelope: envelopeToProto(&e.Envelope), Payload: e.Payload}
+	case *CommandResponse:
+		m = &envelopepb.CommandResponse{UniqueId: e.UniqueID, StatusCode: int32(e.StatusCode), ResponseBody: e.ResponseBody, Envelope: envelopeToProto(&e.Envelope), Payload: e.Payload}
+	default:
+		return nil, fmt.Errorf("protobuf encoding does not support %T", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (ProtobufEncoding) Unmarshal(data []byte, v interface{}) error {
+	switch e := v.(type) {
+	case *Event:
+		var m envelopepb.Event
+		if err := proto.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		e.Envelope = envelopeFromProto(m.Envelope)
+		e.Timestamp = time.Time{}
+		if m.Timestamp != nil {
+			e.Timestamp = m.Timestamp.AsTime()
+		}
+		e.Type = m.Type
+		e.ARI_Body = json.RawMessage(m.AriBody)
+		e.Payload = m.Payload
+	case *AppStart:
+		var m envelopepb.AppStart
+		if err := proto.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		e.Application, e.DialogID, e.ServerID = m.Application, m.DialogId, m.ServerId
+	case *Command:
+		var m envelopepb.Command
+		if err := proto.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		e.UniqueID, e.URL, e.Method, e.Body = m.UniqueId, m.Url, m.Method, m.Body
+		e.Envelope = envelopeFromProto(m.Envelope)
+		e.Signature = m.Signature
+	case *CommandResponse:
+		var m envelopepb.CommandResponse
+		if err := proto.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		e.UniqueID, e.StatusCode, e.ResponseBody = m.UniqueId, int(m.StatusCode), m.ResponseBody
+		e.Payload = m.Payload
+		e.Envelope = envelopeFromProto(m.Envelope)
+	default:
+		return fmt.Errorf("protobuf encoding does not support %T", v)
+	}
+	return nil
+}
+
+// checkContentType warns about a message whose content type, as carried in
+// the headers of buses that support them, differs from the selected encoding.
+func checkContentType(topic string, contentType string) {
+	if contentType != "" && contentType != ContentType() {
+		logger.Warn("message content type differs from the bus encoding", LogTopic, topic, "content_type", contentType, "encoding", ContentType())
+	}
+}
+
+// eventToProto converts an Event to its protocol buffer form.
+func eventToProto(e *Event) *envelopepb.Event {
+	return &envelopepb.Event{
+		Timestamp: timestamppb.New(e.Timestamp),
+		Type:      e.Type,
+		AriBody:   string(e.ARI_Body),
+		Envelope:  envelopeToProto(&e.Envelope),
+		Payload:   e.Payload,
+	}
+}
+
+// envelopeToProto converts an Envelope to its protocol buffer form.
+func envelopeToProto(e *Envelope) *envelopepb.Envelope {
+	return &envelopepb.Envelope{
+		Version:         uint32(e.Version),
+		DialogId:        e.DialogID,
+		Application:     e.Application,
+		ServerId:        e.ServerID,
+		Sequence:        e.Sequence,
+		ProxyInstanceId: e.ProxyInstanceID,
+		Compression:     e.Compression,
+		Encryption:      e.Encryption,
+		KeyId:           e.KeyID,
+		Trace:           e.Trace,
+	}
+}
+
+// envelopeFromProto converts the protocol buffer form of an Envelope back.
+func envelopeFromProto(m *envelopepb.Envelope) Envelope {
+	return Envelope{
+		Version:         int(m.GetVersion()),
+		DialogID:        m.GetDialogId(),
+		Application:     m.GetApplication(),
+		ServerID:        m.GetServerId(),
+		Sequence:        m.GetSequence(),
+		ProxyInstanceID: m.GetProxyInstanceId(),
+		Compression:     m.GetCompression(),
+		Encryption:      m.GetEncryption(),
+		KeyID:           m.GetKeyId(),
+		Trace:           TraceContext(m.GetTrace()),
+	}
+}
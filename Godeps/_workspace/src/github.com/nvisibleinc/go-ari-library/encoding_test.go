@@ -0,0 +1,88 @@
+package ari
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// encodings are the encodings of bus messages, by the name SetEncoding takes.
+var encodings = []struct {
+	name     string
+	encoding Encoding
+}{
+	{"json", JSONEncoding{}},
+	{"protobuf", ProtobufEncoding{}},
+	{"msgpack", MsgpackEncoding{}},
+	{"cbor", CBOREncoding{}},
+}
+
+// benchmarkEnvelope is the envelope of a dialog message as the proxy sends it.
+var benchmarkEnvelope = Envelope{
+	Version:         EnvelopeVersion,
+	DialogID:        "0c7bc1f1-7b5e-4b5a-8a4e-2a3f5d6e7f80",
+	Application:     "bench",
+	ServerID:        "proxy-1",
+	Sequence:        42,
+	ProxyInstanceID: "9d2f0c1e-5a4b-4c3d-8e7f-1a2b3c4d5e6f",
+	Trace:           TraceContext{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+}
+
+// benchmarkMessages returns a message of each kind the bus carries, along
+// with a function returning a value to decode it into.
+func benchmarkMessages() []struct {
+	name    string
+	message interface{}
+	decoded func() interface{}
+} {
+	body := json.RawMessage(`{"type":"ChannelStateChange","timestamp":"2015-01-01T12:00:00.000+0000","channel":{"id":"1420113600.1","name":"PJSIP/alice-00000001","state":"Up","caller":{"name":"Alice","number":"1000"},"connected":{"name":"","number":""},"accountcode":"","dialplan":{"context":"default","exten":"1000","priority":1},"creationtime":"2015-01-01T12:00:00.000+0000","language":"en"},"application":"bench"}`)
+	return []struct {
+		name    string
+		message interface{}
+		decoded func() interface{}
+	}{
+		{"Event", &Event{Envelope: benchmarkEnvelope, Timestamp: time.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC), Type: "ChannelStateChange", ARI_Body: body},
+			func() interface{} { return new(Event) }},
+		{"AppStart", &AppStart{Application: "bench", DialogID: benchmarkEnvelope.DialogID, ServerID: "proxy-1"},
+			func() interface{} { return new(AppStart) }},
+		{"Command", &Command{Envelope: benchmarkEnvelope, UniqueID: "7f1c2d3e-4b5a-6978-8a9b-0c1d2e3f4a5b", URL: "/channels/1420113600.1/play", Method: "POST", Body: `{"media":"sound:hello-world"}`},
+			func() interface{} { return new(Command) }},
+		{"CommandResponse", &CommandResponse{Envelope: benchmarkEnvelope, UniqueID: "7f1c2d3e-4b5a-6978-8a9b-0c1d2e3f4a5b", StatusCode: 201, ResponseBody: `{"id":"playback-1","media_uri":"sound:hello-world","target_uri":"channel:1420113600.1","language":"en","state":"queued"}`},
+			func() interface{} { return new(CommandResponse) }},
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	for _, e := range encodings {
+		for _, m := range benchmarkMessages() {
+			b.Run(e.name+"/"+m.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := e.encoding.Marshal(m.message); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, e := range encodings {
+		for _, m := range benchmarkMessages() {
+			data, err := e.encoding.Marshal(m.message)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(e.name+"/"+m.name, func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := e.encoding.Unmarshal(data, m.decoded()); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
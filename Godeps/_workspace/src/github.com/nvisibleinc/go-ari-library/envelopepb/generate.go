@@ -0,0 +1,5 @@
+// Package envelopepb holds the protocol buffer form of the bus messages, as
+// used by the protobuf encoding.
+package envelopepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative envelope.proto
@@ -1,17 +1,25 @@
 package ari
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
 // global variables
 var bus MessageBus // global var that holds the current MessageBus interface.
+var publishErrorHandler func(topic string, err error)
+
+// dialogTopics are the kinds of per-dialog topics, named <kind>_<dialogID>,
+// that connect a proxy instance with its application instance.
+var dialogTopics = []string{"events", "commands", "responses"}
 
 // MessageBus interface contains methods for interacting with the abstracted message bus.
 type MessageBus interface {
@@ -21,6 +29,19 @@ type MessageBus interface {
 	TopicExists(topic string) bool
 }
 
+// HealthChecker is implemented by message buses that can tell whether their
+// connection to the broker is up. Checks that reach out to the broker give up
+// when the context is done.
+type HealthChecker interface {
+	Healthy(ctx context.Context) error
+}
+
+// TopicCloser is implemented by message buses that hold resources for a topic
+// which can be released once the topic is no longer used.
+type TopicCloser interface {
+	CloseTopic(topic string) error
+}
+
 // AppInstanceHandler when you start a new App, you pass in a function of type AppInstanceHandler.
 // The entry point of the execution of an application instance.
 type AppInstanceHandler func(*AppInstance)
@@ -36,18 +57,72 @@ type App struct {
 // AppInstance struct contains the channels necessary for communication to/from
 // the various message bus topics and the event channel.
 type AppInstance struct {
+	dialogID        string
+	application     string
+	commandLock     sync.Mutex
+	commandSequence uint64
+	trace           TraceContext // trace the commands belong to, guarded by commandLock
 	commandChannel  chan []byte
 	responseChannel chan *CommandResponse
 	quit            chan int
 	Events          chan *Event
 }
 
+// EnvelopeVersion is the current version of the message envelope. Version 1
+// carried the ARI body of an Event as a JSON encoded string, version 2 embeds
+// it as raw JSON.
+const EnvelopeVersion = 2
+
+// Envelope struct contains the metadata common to the messages of a dialog,
+// which lets consumers detect gaps, duplicates and misrouted messages.
+// Sequence numbers count the messages of a dialog on each of its topics,
+// starting at 1, and zero means the sender did not number the message.
+type Envelope struct {
+	Version         int          `json:"version,omitempty"`
+	DialogID        string       `json:"dialog_id,omitempty"`
+	Application     string       `json:"application,omitempty"`
+	ServerID        string       `json:"server_id,omitempty"`
+	Sequence        uint64       `json:"sequence,omitempty"`
+	ProxyInstanceID string       `json:"proxy_instance_id,omitempty"` // proxy process serving the dialog
+	Compression     string       `json:"compression,omitempty"`       // algorithm of the compressed payload, if any
+	Encryption      string       `json:"encryption,omitempty"`        // cipher of the encrypted payload, if any
+	KeyID           string       `json:"key_id,omitempty"`            // key the message is signed or encrypted with
+	Trace           TraceContext `json:"trace,omitempty"`             // trace the message belongs to, if any
+}
+
+// TraceContext carries the context of a distributed trace, such as the W3C
+// traceparent and tracestate headers, from the sender of a message to its
+// consumer. It implements the TextMapCarrier of OpenTelemetry propagators.
+type TraceContext map[string]string
+
+// Get returns the value of a key.
+func (t TraceContext) Get(key string) string {
+	return t[key]
+}
+
+// Set sets the value of a key. It has no effect on a nil TraceContext.
+func (t TraceContext) Set(key string, value string) {
+	if t != nil {
+		t[key] = value
+	}
+}
+
+// Keys lists the keys of the TraceContext.
+func (t TraceContext) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 // Event struct contains the events we pull off the websocket connection.
 type Event struct {
-	ServerID  string    `json:"server_id"`
-	Timestamp time.Time `json:"timestamp"`
-	Type      string    `json:"type"`
-	ARI_Body  string    `json:"ari_body"`
+	Envelope
+	Timestamp time.Time       `json:"timestamp"`
+	Type      string          `json:"type"`
+	ARI_Body  json.RawMessage `json:"ari_body"`
+	Payload   []byte          `json:"payload,omitempty"` // compressed ARI body
 }
 
 // AppStart struct contains the initial information for the start of a new application instance.
@@ -59,20 +134,26 @@ type AppStart struct {
 
 // Command struct contains the command we're passing back to ARI.
 type Command struct {
-	UniqueID string `json:"unique_id"`
-	URL      string `json:"url"`
-	Method   string `json:"method"`
-	Body     string `json:"body"`
+	Envelope
+	UniqueID  string `json:"unique_id"`
+	URL       string `json:"url"`
+	Method    string `json:"method"`
+	Body      string `json:"body"`
+	Signature []byte `json:"signature,omitempty"`
 }
 
 // CommandResponse struct contains the response to a Command
 type CommandResponse struct {
+	Envelope
 	UniqueID     string `json:"unique_id"`
 	StatusCode   int    `json:"status_code"`
 	ResponseBody string `json:"response_body"`
+	Payload      []byte `json:"payload,omitempty"` // compressed response body
 }
 
 // InitLogger is a wrapper function to provide a sane interface to logging messages.
+//
+// Deprecated: use NewLogger and SetLogger for structured, leveled logs.
 func InitLogger(handle io.Writer, prefix string) *log.Logger {
 	return log.New(handle, strings.Join([]string{prefix, ": "}, ""), log.Ldate|log.Ltime|log.Lshortfile)
 }
@@ -99,14 +180,40 @@ func InitBus(busType string, config interface{}) error {
 		bus = new(NATS)
 	case "OSLO":
 		// Start an OSLO producer
-		log.Fatal("OSLO message bus producer is not yet implemented.")
+		return errors.New("OSLO message bus producer is not yet implemented")
 	case "RABBITMQ":
 		// Start a RabbitMQ producer
 		bus = new(RabbitMQ)
+	case "KAFKA":
+		// Start a Kafka producer
+		bus = new(Kafka)
+	case "REDIS":
+		// Start a Redis Streams producer
+		bus = new(Redis)
+	case "MQTT":
+		// Start an MQTT producer
+		bus = new(MQTT)
+	case "MEMORY":
+		// Start an in-process bus
+		bus = new(Memory)
+	case "WEBHOOK":
+		// Start delivering to HTTP endpoints
+		bus = new(Webhook)
 	default:
-		log.Fatal("No bus type was specified for the producer that we recognize.")
+		return fmt.Errorf("unknown message bus %q", busType)
+	}
+	return bus.InitBus(config)
+}
+
+// BusHealthy returns an error when the message bus is not initialized or,
+// for buses which implement HealthChecker, when its connection is down.
+func BusHealthy(ctx context.Context) error {
+	if bus == nil {
+		return errors.New("message bus is not initialized")
+	}
+	if h, ok := bus.(HealthChecker); ok {
+		return h.Healthy(ctx)
 	}
-	bus.InitBus(config)
 	return nil
 }
 
@@ -128,6 +235,63 @@ func TopicExists(topic string) <-chan bool {
 	return c
 }
 
+// CloseTopic tells the message bus that a topic is no longer in use, for
+// buses which implement TopicCloser.
+func CloseTopic(topic string) error {
+	if c, ok := bus.(TopicCloser); ok {
+		return c.CloseTopic(topic)
+	}
+	return nil
+}
+
+// CloseDialogTopics closes the events, commands and responses topics of a
+// dialog once it has ended.
+func CloseDialogTopics(dialogID string) {
+	for _, kind := range dialogTopics {
+		CloseTopic(strings.Join([]string{kind, dialogID}, "_"))
+	}
+}
+
+// OnPublishError sets a function the message buses call, besides logging
+// the error, whenever they fail to publish a message. It must be set before
+// the bus is initialized.
+func OnPublishError(handler func(topic string, err error)) {
+	publishErrorHandler = handler
+}
+
+// publishFailed logs a message a bus failed to publish and reports it to the
+// publish error handler.
+func publishFailed(busType string, topic string, err error) {
+	logger.Error("unable to publish message", "bus", busType, LogTopic, topic, LogError, err)
+	if publishErrorHandler != nil {
+		publishErrorHandler(topic, err)
+	}
+}
+
+// isDialogTopic reports whether a topic is one of the per-dialog topics.
+func isDialogTopic(topic string) bool {
+	_, ok := DialogTopicKind(topic)
+	return ok
+}
+
+// DialogTopicKind returns the kind of a per-dialog topic, such as "events",
+// and false for the other topics, such as the signalling topics of the
+// applications.
+func DialogTopicKind(topic string) (string, bool) {
+	for _, kind := range dialogTopics {
+		if strings.HasPrefix(topic, kind+"_") {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// splitDialogTopic splits a per-dialog topic into its kind and dialog ID.
+func splitDialogTopic(topic string) (string, string) {
+	i := strings.Index(topic, "_")
+	return topic[:i], topic[i+1:]
+}
+
 // NewApp creates a new signalling channel for use by an application.
 func NewApp() *App {
 	var a App
@@ -143,9 +307,10 @@ func (a *App) Init(app string, handler AppInstanceHandler) {
 	go func(app string, a *App) {
 		for event := range a.Events {
 			var as AppStart
-			json.Unmarshal(event, &as)
+			Unmarshal(event, &as)
 			if as.Application == app {
 				ai := new(AppInstance)
+				ai.application = as.Application
 				ai.InitAppInstance(as.DialogID)
 				go handler(ai)
 			}
@@ -162,33 +327,42 @@ func NewAppInstance() *AppInstance {
 // InitAppInstance initializes the set of resources necessary for a new application instance.
 func (a *AppInstance) InitAppInstance(instanceID string) {
 	var err error
+	a.dialogID = instanceID
 	a.Events = make(chan *Event)
 	a.responseChannel = make(chan *CommandResponse)
 	commandTopic := strings.Join([]string{"commands", instanceID}, "_")
-	fmt.Println("Command topic is: ", commandTopic)
+	logger.Debug("starting application instance", LogDialogID, instanceID, LogTopic, commandTopic)
 	responseTopic := strings.Join([]string{"responses", instanceID}, "_")
 	a.commandChannel, err = bus.StartProducer(commandTopic)
 	a.commandChannel <- []byte("DUMMY")
 	if err != nil {
-		fmt.Println(err)
+		logger.Error("unable to start producer", LogTopic, commandTopic, LogError, err)
 	}
 	eventBus, err := bus.StartConsumer(strings.Join([]string{"events", instanceID}, "_"))
 	if err != nil {
-		fmt.Println(err)
+		logger.Error("unable to start consumer", LogTopic, strings.Join([]string{"events", instanceID}, "_"), LogError, err)
 	}
-	processEvents(eventBus, a.Events)
+	processEvents(eventBus, a.Events, NewSequenceCheck(instanceID, strings.Join([]string{"events", instanceID}, "_")), a.application)
 	responseBus, err := bus.StartConsumer(responseTopic)
 	if err != nil {
-		fmt.Println(err)
+		logger.Error("unable to start consumer", LogTopic, responseTopic, LogError, err)
 	}
-	a.processCommandResponses(responseBus, a.responseChannel)
+	a.processCommandResponses(responseBus, a.responseChannel, NewSequenceCheck(instanceID, responseTopic))
+}
+
+// Close releases the topics of the application instance on the message bus
+// once its dialog has ended. Buses which learn the application of a dialog,
+// such as MQTT, forget it. No more events are delivered on Events, nor
+// responses to commands.
+func (a *AppInstance) Close() {
+	CloseDialogTopics(a.dialogID)
 }
 
 // InitProducer initializes a new message bus producer.
 func InitProducer(topic string) chan []byte {
 	producer, err := bus.StartProducer(topic)
 	if err != nil {
-		fmt.Println(err)
+		logger.Error("unable to start producer", LogTopic, topic, LogError, err)
 	}
 	return producer
 }
@@ -197,7 +371,7 @@ func InitProducer(topic string) chan []byte {
 func InitConsumer(topic string) chan []byte {
 	consumer, err := bus.StartConsumer(topic)
 	if err != nil {
-		fmt.Println(err)
+		logger.Error("unable to start consumer", LogTopic, topic, LogError, err)
 	}
 	return consumer
 }
@@ -205,33 +379,101 @@ func InitConsumer(topic string) chan []byte {
 // processEvents pulls messages off the inboundEvents channel.
 // Takes the events which were pulled off the bus, converts them to Event, and
 // places onto the parsedEvents channel.
-func processEvents(inboundEvents chan []byte, parsedEvents chan *Event) {
+// Events that fail to decrypt, misrouted and duplicate events are dropped.
+func processEvents(inboundEvents chan []byte, parsedEvents chan *Event, check *SequenceCheck, application string) {
 	go func(inboundEvents chan []byte, parsedEvents chan *Event) {
 		for event := range inboundEvents {
 			var e Event
-			json.Unmarshal(event, &e)
+			Unmarshal(event, &e)
+			// decrypt first, so that forged events cannot disturb the sequence
+			if err := e.Decrypt(keysFor(application)); err != nil {
+				logger.Warn("dropping event", LogDialogID, e.DialogID, LogEventType, e.Type, "sequence", e.Sequence, LogError, err)
+				continue
+			}
+			if !check.Accept(e.Envelope) {
+				continue
+			}
+			if err := e.Decompress(); err != nil {
+				logger.Warn("dropping event", LogDialogID, e.DialogID, LogEventType, e.Type, "sequence", e.Sequence, LogError, err)
+				continue
+			}
+			e.upgrade()
 			parsedEvents <- &e
 		}
 	}(inboundEvents, parsedEvents)
 }
 
+// AsVersion returns a copy of the Event in the given envelope version, so
+// that a proxy can keep serving applications built against older releases of
+// the library while they are upgraded. Version 1 is only meaningful with the
+// JSON encoding.
+func (e Event) AsVersion(version int) Event {
+	e.upgrade()
+	if version < 2 {
+		e.ARI_Body, _ = json.Marshal(string(e.ARI_Body))
+	}
+	e.Version = version
+	return e
+}
+
+// upgrade converts a version 1 Event to the current envelope by unquoting its
+// ARI body. Events without a version field are version 1.
+func (e *Event) upgrade() {
+	if e.Version >= 2 {
+		return
+	}
+	var body string
+	if json.Unmarshal(e.ARI_Body, &body) == nil {
+		e.ARI_Body = json.RawMessage(body)
+	}
+	e.Version = EnvelopeVersion
+}
+
+// SetTraceContext sets the trace context the application instance's
+// following commands carry, so that the proxy's spans for them join the
+// application's trace. An application typically extracts the context of an
+// Event's trace, starts its own span in it and injects that span here.
+func (a *AppInstance) SetTraceContext(t TraceContext) {
+	a.commandLock.Lock()
+	defer a.commandLock.Unlock()
+	a.trace = t
+}
+
+// CommandTimeout is how long an application instance waits for the response
+// to a command. Commands without a response in time get an empty
+// CommandResponse, with a zero StatusCode.
+const CommandTimeout = 5 * time.Second
+
 // processCommand is executing the remote command.
 // Performs the work of marshaling the command, sending it across the bus, and
 // then unmarshaling the data in order to return a command response.
 func (a *AppInstance) processCommand(url string, body string, method string) *CommandResponse {
-	jsonMessage, err := json.Marshal(Command{URL: url, Method: method, Body: body})
+	// number and send the command under the lock, so the sequence numbers
+	// reach the bus in order
+	a.commandLock.Lock()
+	a.commandSequence++
+	c := Command{URL: url, Method: method, Body: body}
+	c.Envelope = Envelope{Version: EnvelopeVersion, DialogID: a.dialogID, Application: a.application, Sequence: a.commandSequence, Trace: a.trace}
+	var jsonMessage []byte
+	err := c.Sign(keysFor(a.application))
+	if err == nil {
+		jsonMessage, err = Marshal(c)
+	}
 	if err != nil {
+		a.commandSequence--
+		a.commandLock.Unlock()
 		return &CommandResponse{}
 	}
-
 	a.commandChannel <- jsonMessage
+	a.commandLock.Unlock()
+
 	for {
 		select {
 		case r, r_ok := <-a.responseChannel:
 			if r_ok {
 				return r
 			}
-		case <-time.After(5 * time.Second):
+		case <-time.After(CommandTimeout):
 			return &CommandResponse{}
 		}
 	}
@@ -240,12 +482,60 @@ func (a *AppInstance) processCommand(url string, body string, method string) *Co
 // processCommandResponses is a function for parsing the Command-Response.
 // processCommandResponses spawns an anonymous go routine which will listen for
 // information on the channel and process them as they arrive.
-func (a *AppInstance) processCommandResponses(fromBus chan []byte, toAppInstance chan *CommandResponse) {
+func (a *AppInstance) processCommandResponses(fromBus chan []byte, toAppInstance chan *CommandResponse, check *SequenceCheck) {
 	go func(fromBus chan []byte, toAppInstance chan *CommandResponse) {
 		for response := range fromBus {
 			var cr CommandResponse
-			json.Unmarshal(response, &cr)
+			Unmarshal(response, &cr)
+			if !check.Accept(cr.Envelope) {
+				continue
+			}
+			if err := cr.Decompress(); err != nil {
+				logger.Warn("dropping response", LogDialogID, cr.DialogID, LogUniqueID, cr.UniqueID, LogError, err)
+				continue
+			}
 			toAppInstance <- &cr
 		}
 	}(fromBus, toAppInstance)
 }
+
+// SequenceCheck tracks the envelopes of the messages on one topic of a dialog.
+// It is not safe for concurrent use.
+type SequenceCheck struct {
+	dialogID        string
+	topic           string
+	proxyInstanceID string
+	last            uint64
+}
+
+// NewSequenceCheck creates a SequenceCheck for a topic of a dialog.
+func NewSequenceCheck(dialogID string, topic string) *SequenceCheck {
+	return &SequenceCheck{dialogID: dialogID, topic: topic}
+}
+
+// Accept reports whether a message should be processed. Messages of another
+// dialog and messages whose sequence number was already seen are rejected,
+// and gaps in the sequence are logged. Numbering starts over when the message
+// comes from another proxy process.
+func (s *SequenceCheck) Accept(e Envelope) bool {
+	if e.DialogID != "" && e.DialogID != s.dialogID {
+		logger.Warn("dropping misrouted message", LogDialogID, e.DialogID, LogTopic, s.topic)
+		return false
+	}
+	if e.ProxyInstanceID != s.proxyInstanceID {
+		s.proxyInstanceID = e.ProxyInstanceID
+		s.last = 0
+	}
+	if e.Sequence == 0 {
+		return true
+	}
+	if e.Sequence <= s.last {
+		logger.Warn("dropping duplicate message", LogDialogID, s.dialogID, LogTopic, s.topic, "sequence", e.Sequence)
+		return false
+	}
+	if e.Sequence > s.last+1 {
+		logger.Warn("missed messages", LogDialogID, s.dialogID, LogTopic, s.topic, "sequence", e.Sequence, "missed", e.Sequence-s.last-1)
+	}
+	s.last = e.Sequence
+	return true
+}
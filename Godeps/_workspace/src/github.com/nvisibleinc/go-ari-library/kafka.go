@@ -0,0 +1,449 @@
+package ari
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kafkaDialTimeout bounds each attempt of the health check to reach a broker.
+const kafkaDialTimeout = 2 * time.Second
+
+// kafkaSeenTTL is how long the dialogs of the messages read from a shared
+// dialog topic are remembered, well beyond the time an application instance
+// takes to start.
+const kafkaSeenTTL = time.Minute
+
+type kafkaConfig struct {
+	Brokers           []string `json:"brokers"`
+	TopicPrefix       string   `json:"topic_prefix"`
+	Acks              string   `json:"acks"`
+	GroupID           string   `json:"group_id"`
+	Partitions        int      `json:"partitions"`
+	ReplicationFactor int      `json:"replication_factor"`
+	BatchTimeout      int      `json:"batch_timeout_ms"`
+}
+
+// Kafka implements the MessageBus interface on top of Apache Kafka.
+// Application signalling topics map to a Kafka topic of their own, which is
+// consumed through a consumer group so that AppStarts are spread across the
+// application instances. Dialog topics share one Kafka topic per kind and use
+// the dialog ID as the partition key, so a dialog's messages stay ordered.
+type Kafka struct {
+	config  kafkaConfig
+	acks    kafka.RequiredAcks
+	lock    sync.Mutex
+	writers map[string]*kafka.Writer
+	demuxes map[string]*kafkaDemux
+	// conn is kept open for the metadata requests of TopicExists, which the
+	// proxy polls, and dialed again once it fails
+	conn     *kafka.Conn
+	connLock sync.Mutex
+}
+
+// kafkaDemux reads every partition of a shared dialog topic and hands each
+// message to the consumer registered for its key (the dialog ID).
+type kafkaDemux struct {
+	lock      sync.Mutex
+	consumers map[string]*kafkaConsumer
+	seen      map[string]time.Time // dialogs with a message read, by the time of their last one
+	pruned    time.Time            // last time seen was pruned
+}
+
+// kafkaConsumer queues the messages of a dialog for its consumer channel
+// until the topic is closed.
+type kafkaConsumer struct {
+	queue chan []byte
+	done  chan bool
+}
+
+func (k *Kafka) InitBus(config interface{}) error {
+	var err error
+	k.config = kafkaConfig{
+		Acks:              "all",
+		GroupID:           "go-ari-library",
+		Partitions:        12,
+		ReplicationFactor: 1,
+		BatchTimeout:      10,
+	}
+	c := config.(map[string]interface{})
+	for key, value := range c {
+		switch key {
+		case "brokers":
+			for _, broker := range value.([]interface{}) {
+				k.config.Brokers = append(k.config.Brokers, broker.(string))
+			}
+		case "topic_prefix":
+			k.config.TopicPrefix = value.(string)
+		case "acks":
+			k.config.Acks = value.(string)
+		case "group_id":
+			k.config.GroupID = value.(string)
+		case "partitions":
+			k.config.Partitions = int(value.(float64))
+		case "replication_factor":
+			k.config.ReplicationFactor = int(value.(float64))
+		case "batch_timeout_ms":
+			k.config.BatchTimeout = int(value.(float64))
+		}
+	}
+	if len(k.config.Brokers) == 0 {
+		return fmt.Errorf("kafka: no brokers configured")
+	}
+
+	switch strings.ToLower(k.config.Acks) {
+	case "none", "0":
+		k.acks = kafka.RequireNone
+	case "leader", "one", "1":
+		k.acks = kafka.RequireOne
+	case "all", "-1":
+		k.acks = kafka.RequireAll
+	default:
+		return fmt.Errorf("kafka: unknown acks setting %q", k.config.Acks)
+	}
+
+	k.writers = make(map[string]*kafka.Writer)
+	k.demuxes = make(map[string]*kafkaDemux)
+
+	// make sure we can reach the cluster before handing out producers
+	conn, err := kafka.Dial("tcp", k.config.Brokers[0])
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (k *Kafka) StartProducer(topic string) (chan []byte, error) {
+	kafkaTopic, key := k.kafkaTopic(topic)
+	if err := k.ensureTopic(kafkaTopic); err != nil {
+		return nil, err
+	}
+	w := k.writer(kafkaTopic)
+	c := make(chan []byte)
+	go func(w *kafka.Writer, key []byte, messages chan []byte) {
+		for message := range messages {
+			err := w.WriteMessages(context.Background(), kafka.Message{
+				Key:     key,
+				Value:   message,
+				Headers: []kafka.Header{{Key: "content-type", Value: []byte(ContentType())}},
+			})
+			if err != nil {
+				publishFailed("kafka", topic, err)
+			}
+		}
+	}(w, key, c)
+	return c, nil
+}
+
+func (k *Kafka) StartConsumer(topic string) (chan []byte, error) {
+	kafkaTopic, key := k.kafkaTopic(topic)
+	if err := k.ensureTopic(kafkaTopic); err != nil {
+		return nil, err
+	}
+	c := make(chan []byte)
+
+	if key == nil {
+		// application signalling topic, balanced over the consumer group
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers: k.config.Brokers,
+			GroupID: k.config.GroupID,
+			Topic:   kafkaTopic,
+		})
+		go func(r *kafka.Reader, c chan []byte) {
+			for {
+				m, err := r.ReadMessage(context.Background())
+				if err != nil {
+					logger.Error("unable to read message", "bus", "kafka", LogTopic, topic, LogError, err)
+					close(c)
+					return
+				}
+				checkContentType(topic, kafkaContentType(m))
+				c <- m.Value
+			}
+		}(r, c)
+		return c, nil
+	}
+
+	d, err := k.demux(kafkaTopic)
+	if err != nil {
+		return nil, err
+	}
+	d.register(string(key), c)
+	return c, nil
+}
+
+// TopicExists reports whether the Kafka topic backing an application
+// signalling topic has been created, either by a producer or a consumer.
+// Dialog topics share their Kafka topic, so a dialog topic only exists once
+// a message of the dialog has been read from it, until kafkaSeenTTL after the
+// last one or until the topic is closed.
+func (k *Kafka) TopicExists(topic string) bool {
+	kafkaTopic, key := k.kafkaTopic(topic)
+	if !k.topicExists(kafkaTopic) {
+		return false
+	}
+	if key == nil {
+		return true
+	}
+	d, err := k.demux(kafkaTopic)
+	if err != nil {
+		return false
+	}
+	return d.hasSeen(string(key))
+}
+
+// CloseTopic stops handing the messages of a dialog topic to its consumer
+// and forgets the dialog. Application signalling topics are left open.
+func (k *Kafka) CloseTopic(topic string) error {
+	kafkaTopic, key := k.kafkaTopic(topic)
+	if key == nil {
+		return nil
+	}
+	k.lock.Lock()
+	d, ok := k.demuxes[kafkaTopic]
+	k.lock.Unlock()
+	if ok {
+		d.unregister(string(key))
+	}
+	return nil
+}
+
+// Healthy returns an error unless one of the brokers can be reached. Each
+// broker is given up to kafkaDialTimeout, so that an unreachable one leaves
+// time for the others.
+func (k *Kafka) Healthy(ctx context.Context) error {
+	var err error
+	for _, broker := range k.config.Brokers {
+		dialCtx, cancel := context.WithTimeout(ctx, kafkaDialTimeout)
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(dialCtx, "tcp", broker)
+		cancel()
+		if err == nil {
+			return conn.Close()
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// topicExists asks the cluster for the partitions of a Kafka topic.
+func (k *Kafka) topicExists(kafkaTopic string) bool {
+	partitions, err := k.partitions(kafkaTopic)
+	return err == nil && len(partitions) > 0
+}
+
+// partitions reads the partitions of a Kafka topic over the connection kept
+// for metadata requests.
+func (k *Kafka) partitions(kafkaTopic string) ([]kafka.Partition, error) {
+	k.connLock.Lock()
+	defer k.connLock.Unlock()
+	if k.conn == nil {
+		conn, err := kafka.Dial("tcp", k.config.Brokers[0])
+		if err != nil {
+			return nil, err
+		}
+		k.conn = conn
+	}
+	partitions, err := k.conn.ReadPartitions(kafkaTopic)
+	var kafkaErr kafka.Error
+	if err != nil && !errors.As(err, &kafkaErr) {
+		// the connection failed rather than the request
+		k.conn.Close()
+		k.conn = nil
+	}
+	return partitions, err
+}
+
+// kafkaTopic maps a bus topic onto a Kafka topic and partition key. The key
+// is nil for application signalling topics.
+func (k *Kafka) kafkaTopic(topic string) (string, []byte) {
+	if !isDialogTopic(topic) {
+		return k.config.TopicPrefix + topic, nil
+	}
+	kind, dialogID := splitDialogTopic(topic)
+	return k.config.TopicPrefix + kind, []byte(dialogID)
+}
+
+// writer returns the shared writer for a Kafka topic, creating it on first use.
+func (k *Kafka) writer(kafkaTopic string) *kafka.Writer {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	w, ok := k.writers[kafkaTopic]
+	if !ok {
+		w = &kafka.Writer{
+			Addr:         kafka.TCP(k.config.Brokers...),
+			Topic:        kafkaTopic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: k.acks,
+			BatchTimeout: time.Duration(k.config.BatchTimeout) * time.Millisecond,
+		}
+		k.writers[kafkaTopic] = w
+	}
+	return w
+}
+
+// ensureTopic creates the Kafka topic through the controller if it does not
+// exist yet.
+func (k *Kafka) ensureTopic(kafkaTopic string) error {
+	if k.topicExists(kafkaTopic) {
+		return nil
+	}
+	conn, err := kafka.Dial("tcp", k.config.Brokers[0])
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	controller, err := conn.Controller()
+	if err != nil {
+		return err
+	}
+	cc, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+	err = cc.CreateTopics(kafka.TopicConfig{
+		Topic:             kafkaTopic,
+		NumPartitions:     k.config.Partitions,
+		ReplicationFactor: k.config.ReplicationFactor,
+	})
+	if errors.Is(err, kafka.TopicAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+// demux returns the demultiplexer for a shared dialog topic, starting a
+// reader on every partition of the topic the first time it is requested.
+// Readers start at the first offset, so that the first message of a dialog
+// is not missed when it was written before the reader was positioned;
+// messages older than kafkaSeenTTL are skipped.
+func (k *Kafka) demux(kafkaTopic string) (*kafkaDemux, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if d, ok := k.demuxes[kafkaTopic]; ok {
+		return d, nil
+	}
+
+	partitions, err := k.partitions(kafkaTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &kafkaDemux{consumers: make(map[string]*kafkaConsumer), seen: make(map[string]time.Time), pruned: time.Now()}
+	for _, p := range partitions {
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     k.config.Brokers,
+			Topic:       kafkaTopic,
+			Partition:   p.ID,
+			StartOffset: kafka.FirstOffset,
+		})
+		go d.run(r)
+	}
+	k.demuxes[kafkaTopic] = d
+	return d, nil
+}
+
+// register attaches a consumer channel to a dialog ID. Messages are queued
+// per dialog so that a slow consumer does not hold up the other dialogs
+// sharing the partition.
+func (d *kafkaDemux) register(key string, c chan []byte) {
+	consumer := &kafkaConsumer{queue: make(chan []byte, 256), done: make(chan bool)}
+	go func(consumer *kafkaConsumer, c chan []byte) {
+		for {
+			select {
+			case message := <-consumer.queue:
+				select {
+				case c <- message:
+				case <-consumer.done:
+					return
+				}
+			case <-consumer.done:
+				return
+			}
+		}
+	}(consumer, c)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.consumers[key] = consumer
+}
+
+// unregister detaches the consumer of a dialog ID, dropping the messages
+// still queued for it.
+func (d *kafkaDemux) unregister(key string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if consumer, ok := d.consumers[key]; ok {
+		close(consumer.done)
+		delete(d.consumers, key)
+	}
+	delete(d.seen, key)
+}
+
+// hasSeen reports whether a message of a dialog ID has been read in the last
+// kafkaSeenTTL, whether or not it was asked for before.
+func (d *kafkaDemux) hasSeen(key string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	_, ok := d.seen[key]
+	return ok
+}
+
+// run reads a single partition and dispatches messages by key. Every dialog
+// read is remembered for TopicExists. Messages for dialogs without a local
+// consumer belong to another process, or to an application instance not
+// consumed yet, and are skipped. A consumer whose queue is full loses the
+// message rather than holding up the partition.
+func (d *kafkaDemux) run(r *kafka.Reader) {
+	topic := r.Config().Topic
+	for {
+		m, err := r.ReadMessage(context.Background())
+		if err != nil {
+			logger.Error("unable to read message", "bus", "kafka", LogTopic, topic, LogError, err)
+			return
+		}
+		if time.Since(m.Time) > kafkaSeenTTL {
+			// left from dialogs that are long gone
+			continue
+		}
+		key := string(m.Key)
+		d.lock.Lock()
+		d.seen[key] = m.Time
+		if time.Since(d.pruned) > kafkaSeenTTL {
+			for id, t := range d.seen {
+				if time.Since(t) > kafkaSeenTTL {
+					delete(d.seen, id)
+				}
+			}
+			d.pruned = time.Now()
+		}
+		consumer, ok := d.consumers[key]
+		if ok {
+			checkContentType(topic, kafkaContentType(m))
+			select {
+			case consumer.queue <- m.Value:
+			default:
+				logger.Warn("dropping message for a slow consumer", "bus", "kafka", LogTopic, topic, LogDialogID, key)
+			}
+		}
+		d.lock.Unlock()
+	}
+}
+
+// kafkaContentType returns the content type header of a message.
+func kafkaContentType(m kafka.Message) string {
+	for _, h := range m.Headers {
+		if h.Key == "content-type" {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
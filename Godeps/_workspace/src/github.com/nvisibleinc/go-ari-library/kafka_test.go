@@ -0,0 +1,157 @@
+package ari
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestKafka connects to the brokers listed in KAFKA_BROKERS, separated by
+// commas, and skips the test when there are none. Every test gets topics of
+// its own through a unique topic prefix.
+func newTestKafka(t *testing.T) *Kafka {
+	t.Helper()
+	brokers := os.Getenv("KAFKA_BROKERS")
+	if brokers == "" {
+		t.Skip("KAFKA_BROKERS is not set")
+	}
+	var list []interface{}
+	for _, broker := range strings.Split(brokers, ",") {
+		list = append(list, broker)
+	}
+	k := new(Kafka)
+	err := k.InitBus(map[string]interface{}{
+		"brokers":      list,
+		"topic_prefix": strings.Join([]string{"test-", UUID(), "."}, ""),
+		"group_id":     strings.Join([]string{"test-", UUID()}, ""),
+		"partitions":   float64(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// publishUntil publishes a message until it is received on a consumer, as
+// the readers of a new topic may join after the first messages.
+func publishUntil(t *testing.T, producer chan []byte, consumer chan []byte, message string) {
+	t.Helper()
+	deadline := time.After(30 * time.Second)
+	for {
+		producer <- []byte(message)
+		select {
+		case m := <-consumer:
+			if string(m) != message {
+				t.Fatalf("received %q, want %q", m, message)
+			}
+			return
+		case <-time.After(500 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("%q was not received", message)
+		}
+	}
+}
+
+// drain takes the repeated messages of publishUntil off a consumer.
+func drain(consumer chan []byte) {
+	for {
+		select {
+		case <-consumer:
+		case <-time.After(2 * time.Second):
+			return
+		}
+	}
+}
+
+func TestKafkaSignallingTopic(t *testing.T) {
+	k := newTestKafka(t)
+	consumer, err := k.StartConsumer("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	producer, err := k.StartProducer("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !k.TopicExists("app") {
+		t.Error("the signalling topic does not exist once started")
+	}
+	publishUntil(t, producer, consumer, "AppStart")
+}
+
+func TestKafkaDialogTopics(t *testing.T) {
+	k := newTestKafka(t)
+	a, b := UUID(), UUID()
+	consumerA, err := k.StartConsumer("events_" + a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumerB, err := k.StartConsumer("events_" + b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	producerA, err := k.StartProducer("events_" + a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	producerB, err := k.StartProducer("events_" + b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishUntil(t, producerA, consumerA, "event of A")
+	publishUntil(t, producerB, consumerB, "event of B")
+}
+
+func TestKafkaDialogTopicExists(t *testing.T) {
+	k := newTestKafka(t)
+	id := UUID()
+	producer, err := k.StartProducer("commands_" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an application instance announces itself with a single message, which
+	// may be written before the proxy first asks for the topic
+	producer <- []byte("DUMMY")
+	time.Sleep(time.Second)
+	for deadline := time.Now().Add(30 * time.Second); !k.TopicExists("commands_" + id); time.Sleep(100 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the dialog topic does not exist after a message")
+		}
+	}
+	if k.TopicExists("commands_" + UUID()) {
+		t.Error("a dialog topic without any message exists")
+	}
+}
+
+func TestKafkaCloseTopic(t *testing.T) {
+	k := newTestKafka(t)
+	id := UUID()
+	consumer, err := k.StartConsumer("responses_" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	producer, err := k.StartProducer("responses_" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishUntil(t, producer, consumer, "response")
+	drain(consumer)
+
+	if err = k.CloseTopic("responses_" + id); err != nil {
+		t.Fatal(err)
+	}
+	d, _ := k.demux(k.config.TopicPrefix + "responses")
+	d.lock.Lock()
+	_, registered := d.consumers[id]
+	d.lock.Unlock()
+	if registered {
+		t.Error("the consumer is still registered after closing the topic")
+	}
+	producer <- []byte("late response")
+	select {
+	case m := <-consumer:
+		t.Errorf("received %q after closing the topic", m)
+	case <-time.After(2 * time.Second):
+	}
+}
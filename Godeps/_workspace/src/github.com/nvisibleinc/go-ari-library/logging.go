@@ -0,0 +1,51 @@
+package ari
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Keys of the fields shared by the log records of the library and its users,
+// so that the records of a dialog can be found across processes.
+const (
+	LogApplication = "application"
+	LogDialogID    = "dialog_id"
+	LogChannelID   = "channel_id"
+	LogUniqueID    = "unique_id"
+	LogEventType   = "event_type"
+	LogTopic       = "topic"
+	LogError       = "error"
+)
+
+// global variables
+var logger = slog.Default() // logger of the library
+
+// SetLogger sets the structured logger the library logs with. It must be set
+// before the bus is initialized.
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// NewLogger creates a structured logger writing records of the level and
+// above to w, formatted as "json" or "text". The level can be changed while
+// the logger is in use.
+func NewLogger(w io.Writer, format string, level *slog.LevelVar) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// ParseLevel parses a log level: debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	err := l.UnmarshalText([]byte(s))
+	return l, err
+}
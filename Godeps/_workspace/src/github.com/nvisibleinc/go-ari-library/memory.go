@@ -0,0 +1,150 @@
+package ari
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Memory implements the MessageBus interface inside the running process. It
+// allows a proxy and its applications to share a single binary, and tests to
+// run without a broker. Consumers of the same topic form a queue group:
+// every message goes to exactly one of them, in round-robin order.
+type Memory struct {
+	buffer int
+	lock   sync.Mutex
+	topics map[string]*memoryTopic
+}
+
+// memoryTopic holds the queued messages and the consumers of a topic.
+type memoryTopic struct {
+	queue     chan []byte
+	lock      sync.Mutex
+	consumers []chan []byte
+	next      int
+	joined    chan bool // signalled when a consumer joins
+	done      chan bool // closed when the topic is closed
+}
+
+func (m *Memory) InitBus(config interface{}) error {
+	m.buffer = 64
+	c, _ := config.(map[string]interface{})
+	for key, value := range c {
+		switch key {
+		case "buffer":
+			buffer, ok := value.(float64)
+			if !ok || buffer < 0 {
+				return fmt.Errorf("memory: invalid buffer %v", value)
+			}
+			m.buffer = int(buffer)
+		}
+	}
+	m.topics = make(map[string]*memoryTopic)
+	return nil
+}
+
+func (m *Memory) StartProducer(topic string) (chan []byte, error) {
+	t := m.topic(topic)
+	c := make(chan []byte)
+	go func(t *memoryTopic, messages chan []byte) {
+		for message := range messages {
+			select {
+			case t.queue <- message:
+			case <-t.done:
+				// the topic was closed, drop what is still published to it
+			}
+		}
+	}(t, c)
+	return c, nil
+}
+
+func (m *Memory) StartConsumer(topic string) (chan []byte, error) {
+	t := m.topic(topic)
+	c := make(chan []byte)
+	t.lock.Lock()
+	t.consumers = append(t.consumers, c)
+	t.lock.Unlock()
+	select {
+	case t.joined <- true:
+	default:
+	}
+	return c, nil
+}
+
+// TopicExists reports whether a producer or consumer has been started on the
+// topic and the topic has not been closed since.
+func (m *Memory) TopicExists(topic string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, ok := m.topics[topic]
+	return ok
+}
+
+// CloseTopic removes the topic and drops any messages still queued on it.
+func (m *Memory) CloseTopic(topic string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if t, ok := m.topics[topic]; ok {
+		close(t.done)
+		delete(m.topics, topic)
+	}
+	return nil
+}
+
+// topic returns the named topic, creating it and starting its dispatcher if
+// it does not exist yet.
+func (m *Memory) topic(topic string) *memoryTopic {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t, ok := m.topics[topic]
+	if !ok {
+		t = &memoryTopic{
+			queue:  make(chan []byte, m.buffer),
+			joined: make(chan bool, 1),
+			done:   make(chan bool),
+		}
+		m.topics[topic] = t
+		go t.dispatch()
+	}
+	return t
+}
+
+// dispatch hands the queued messages to the consumers in turn. Messages wait
+// in the queue until the first consumer has joined.
+func (t *memoryTopic) dispatch() {
+	for {
+		select {
+		case message := <-t.queue:
+			c := t.nextConsumer()
+			if c == nil {
+				return
+			}
+			select {
+			case c <- message:
+			case <-t.done:
+				return
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// nextConsumer picks the consumer to receive the next message, waiting for
+// one to join if there are none. Returns nil once the topic is closed.
+func (t *memoryTopic) nextConsumer() chan []byte {
+	for {
+		t.lock.Lock()
+		if len(t.consumers) > 0 {
+			c := t.consumers[t.next%len(t.consumers)]
+			t.next++
+			t.lock.Unlock()
+			return c
+		}
+		t.lock.Unlock()
+		select {
+		case <-t.joined:
+		case <-t.done:
+			return nil
+		}
+	}
+}
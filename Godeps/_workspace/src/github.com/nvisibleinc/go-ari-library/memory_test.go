@@ -0,0 +1,102 @@
+package ari
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestMemory returns an initialized memory bus.
+func newTestMemory(t *testing.T) *Memory {
+	t.Helper()
+	m := new(Memory)
+	if err := m.InitBus(nil); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// receive waits for a message on a consumer.
+func receive(t *testing.T, consumer chan []byte) string {
+	t.Helper()
+	select {
+	case m := <-consumer:
+		return string(m)
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return ""
+	}
+}
+
+func TestMemoryInitBus(t *testing.T) {
+	m := new(Memory)
+	if err := m.InitBus(map[string]interface{}{"buffer": float64(8)}); err != nil {
+		t.Fatal(err)
+	}
+	if m.buffer != 8 {
+		t.Errorf("buffer is %d, want 8", m.buffer)
+	}
+	for _, buffer := range []interface{}{"8", float64(-1), true} {
+		if err := new(Memory).InitBus(map[string]interface{}{"buffer": buffer}); err == nil {
+			t.Errorf("buffer %#v was accepted", buffer)
+		}
+	}
+}
+
+func TestMemoryRoundRobin(t *testing.T) {
+	m := newTestMemory(t)
+	first, _ := m.StartConsumer("app")
+	second, _ := m.StartConsumer("app")
+	producer, _ := m.StartProducer("app")
+	for _, message := range []string{"1", "2", "3", "4"} {
+		producer <- []byte(message)
+	}
+	if got := receive(t, first) + receive(t, second) + receive(t, first) + receive(t, second); got != "1234" {
+		t.Errorf("consumers received %q in turn, want 1234", got)
+	}
+}
+
+func TestMemoryQueuesUntilConsumerJoins(t *testing.T) {
+	m := newTestMemory(t)
+	producer, _ := m.StartProducer("events_dialog")
+	producer <- []byte("early")
+	if !m.TopicExists("events_dialog") {
+		t.Fatal("the topic does not exist once a producer started")
+	}
+	consumer, _ := m.StartConsumer("events_dialog")
+	if got := receive(t, consumer); got != "early" {
+		t.Errorf("received %q, want early", got)
+	}
+}
+
+func TestMemoryCloseTopic(t *testing.T) {
+	m := newTestMemory(t)
+	consumer, _ := m.StartConsumer("events_dialog")
+	producer, _ := m.StartProducer("events_dialog")
+	producer <- []byte("before")
+	if got := receive(t, consumer); got != "before" {
+		t.Fatalf("received %q, want before", got)
+	}
+
+	if err := m.CloseTopic("events_dialog"); err != nil {
+		t.Fatal(err)
+	}
+	if m.TopicExists("events_dialog") {
+		t.Error("the topic still exists after closing it")
+	}
+	// publishing to a closed topic neither blocks nor delivers
+	for i := 0; i < m.buffer+1; i++ {
+		select {
+		case producer <- []byte("after"):
+		case <-time.After(time.Second):
+			t.Fatal("publishing to a closed topic blocks")
+		}
+	}
+	select {
+	case message := <-consumer:
+		t.Errorf("received %q after closing the topic", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := m.CloseTopic("events_dialog"); err != nil {
+		t.Errorf("closing a closed topic: %s", err)
+	}
+}
@@ -0,0 +1,296 @@
+package ari
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/eclipse/paho.mqtt.golang"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+type mqttConfig struct {
+	URL         string        `json:"url"`
+	ClientID    string        `json:"client_id"`
+	Username    string        `json:"username"`
+	Password    string        `json:"password"`
+	QoS         byte          `json:"qos"`
+	ShareGroup  string        `json:"share_group"`
+	TopicPrefix string        `json:"topic_prefix"`
+	TLS         mqttTLSConfig `json:"tls"`
+}
+
+type mqttTLSConfig struct {
+	CAFile             string `json:"ca_file"`
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// MQTT implements the MessageBus interface on top of an MQTT broker.
+// Topics are mapped onto a hierarchy below the topic prefix (default "ari"):
+// an application's signalling topic becomes <prefix>/<app>/start and is
+// consumed through a shared subscription, so AppStarts are spread across
+// the application instances, while the dialog topics become
+// <prefix>/<app>/<dialogID>/<kind>. Messages are never published retained,
+// and retained messages left on the broker are ignored.
+type MQTT struct {
+	config     mqttConfig
+	client     mqtt.Client
+	lock       sync.RWMutex
+	dialogApps map[string]string        // dialog ID to application, learned from AppStarts
+	consumers  map[string]*mqttConsumer // bus topic to local subscription
+}
+
+// mqttConsumer is the local subscription to a bus topic, whose messages are
+// queued for the consumer channel until the topic is closed.
+type mqttConsumer struct {
+	filter  string
+	handler mqtt.MessageHandler // queues the messages, kept to subscribe again
+	done    chan bool
+}
+
+func (m *MQTT) InitBus(config interface{}) error {
+	m.config = mqttConfig{
+		URL:         "tcp://localhost:1883",
+		ClientID:    "go-ari",
+		QoS:         1,
+		ShareGroup:  "go-ari-library",
+		TopicPrefix: "ari",
+	}
+	c := config.(map[string]interface{})
+	for key, value := range c {
+		switch key {
+		case "url":
+			m.config.URL = value.(string)
+		case "client_id":
+			m.config.ClientID = value.(string)
+		case "username":
+			m.config.Username = value.(string)
+		case "password":
+			m.config.Password = value.(string)
+		case "qos":
+			m.config.QoS = byte(value.(float64))
+		case "share_group":
+			m.config.ShareGroup = value.(string)
+		case "topic_prefix":
+			m.config.TopicPrefix = value.(string)
+		case "tls":
+			// round trip through json to fill in the nested struct
+			j, _ := json.Marshal(value)
+			if err := json.Unmarshal(j, &m.config.TLS); err != nil {
+				return err
+			}
+		}
+	}
+	if m.config.QoS > 2 {
+		return fmt.Errorf("mqtt: invalid qos %d", m.config.QoS)
+	}
+	m.dialogApps = make(map[string]string)
+	m.consumers = make(map[string]*mqttConsumer)
+
+	opts := mqtt.NewClientOptions()
+	opts.AddBroker(m.config.URL)
+	// client IDs must be unique per broker, so every process gets its own
+	opts.SetClientID(strings.Join([]string{m.config.ClientID, UUID()}, "-"))
+	opts.SetUsername(m.config.Username)
+	opts.SetPassword(m.config.Password)
+	opts.SetCleanSession(true)
+	opts.SetAutoReconnect(true)
+	// the broker forgets the subscriptions of a clean session when the
+	// connection drops, so they are made again on every reconnect
+	opts.SetOnConnectHandler(m.resubscribe)
+	if m.config.TLS.CAFile != "" || m.config.TLS.CertFile != "" || m.config.TLS.InsecureSkipVerify {
+		tlsConfig, err := m.tlsConfig()
+		if err != nil {
+			return err
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	m.client = mqtt.NewClient(opts)
+	token := m.client.Connect()
+	token.Wait()
+	return token.Error()
+}
+
+// Healthy returns an error unless the client is connected to the broker.
+func (m *MQTT) Healthy(ctx context.Context) error {
+	if !m.client.IsConnectionOpen() {
+		return errors.New("mqtt: not connected to the broker")
+	}
+	return nil
+}
+
+func (m *MQTT) StartProducer(topic string) (chan []byte, error) {
+	c := make(chan []byte)
+	go func(topic string, messages chan []byte) {
+		for message := range messages {
+			if !isDialogTopic(topic) {
+				m.learnDialog(message)
+			}
+			token := m.client.Publish(m.mqttTopic(topic), m.config.QoS, false, message)
+			token.Wait()
+			if token.Error() != nil {
+				publishFailed("mqtt", topic, token.Error())
+			}
+		}
+	}(topic, c)
+	return c, nil
+}
+
+func (m *MQTT) StartConsumer(topic string) (chan []byte, error) {
+	var filter string
+	if isDialogTopic(topic) {
+		// the consumer of a dialog topic may not have seen the AppStart yet,
+		// so match the dialog below any application
+		kind, dialogID := splitDialogTopic(topic)
+		filter = strings.Join([]string{m.config.TopicPrefix, "+", dialogID, kind}, "/")
+	} else {
+		filter = strings.Join([]string{"$share", m.config.ShareGroup, m.mqttTopic(topic)}, "/")
+	}
+
+	c := make(chan []byte)
+	queue := make(chan []byte, 256)
+	consumer := &mqttConsumer{filter: filter, done: make(chan bool)}
+	go func(queue chan []byte, c chan []byte, done chan bool) {
+		for {
+			select {
+			case message := <-queue:
+				if !isDialogTopic(topic) {
+					m.learnDialog(message)
+				}
+				select {
+				case c <- message:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}(queue, c, consumer.done)
+
+	// the client dispatches the messages of all subscriptions from a single
+	// goroutine, so a full queue loses the message rather than stalling it
+	consumer.handler = func(client mqtt.Client, message mqtt.Message) {
+		if message.Retained() {
+			return
+		}
+		select {
+		case queue <- message.Payload():
+		default:
+			logger.Warn("dropping message for a slow consumer", "bus", "mqtt", LogTopic, topic)
+		}
+	}
+	token := m.client.Subscribe(filter, m.config.QoS, consumer.handler)
+	token.Wait()
+	if token.Error() != nil {
+		close(consumer.done)
+		return nil, token.Error()
+	}
+
+	m.lock.Lock()
+	m.consumers[topic] = consumer
+	m.lock.Unlock()
+	return c, nil
+}
+
+// resubscribe subscribes every local consumer again once the client has
+// connected. It has nothing to do on the first connection.
+func (m *MQTT) resubscribe(client mqtt.Client) {
+	m.lock.RLock()
+	consumers := make([]*mqttConsumer, 0, len(m.consumers))
+	for _, consumer := range m.consumers {
+		consumers = append(consumers, consumer)
+	}
+	m.lock.RUnlock()
+	for _, consumer := range consumers {
+		token := client.Subscribe(consumer.filter, m.config.QoS, consumer.handler)
+		token.Wait()
+		if token.Error() != nil {
+			logger.Error("unable to subscribe again", "bus", "mqtt", "filter", consumer.filter, LogError, token.Error())
+		}
+	}
+}
+
+// TopicExists always returns true, as MQTT topics exist implicitly.
+func (m *MQTT) TopicExists(topic string) bool {
+	return true
+}
+
+// CloseTopic drops the local subscription to a topic, stops handing its
+// messages to the consumer and forgets the application of a dialog once its
+// topics are closed.
+func (m *MQTT) CloseTopic(topic string) error {
+	m.lock.Lock()
+	consumer, ok := m.consumers[topic]
+	delete(m.consumers, topic)
+	if isDialogTopic(topic) {
+		_, dialogID := splitDialogTopic(topic)
+		delete(m.dialogApps, dialogID)
+	}
+	m.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	close(consumer.done)
+	token := m.client.Unsubscribe(consumer.filter)
+	token.Wait()
+	return token.Error()
+}
+
+// mqttTopic maps a bus topic onto the MQTT topic hierarchy. Dialogs whose
+// AppStart has not been seen by this process are published below "_".
+func (m *MQTT) mqttTopic(topic string) string {
+	if !isDialogTopic(topic) {
+		return strings.Join([]string{m.config.TopicPrefix, topic, "start"}, "/")
+	}
+	kind, dialogID := splitDialogTopic(topic)
+	m.lock.RLock()
+	app, ok := m.dialogApps[dialogID]
+	m.lock.RUnlock()
+	if !ok {
+		app = "_"
+	}
+	return strings.Join([]string{m.config.TopicPrefix, app, dialogID, kind}, "/")
+}
+
+// learnDialog records the application of a dialog from an AppStart passing
+// over a signalling topic.
+func (m *MQTT) learnDialog(message []byte) {
+	var as AppStart
+	if Unmarshal(message, &as) != nil || as.DialogID == "" {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dialogApps[as.DialogID] = as.Application
+}
+
+// tlsConfig builds the TLS configuration from the configured files.
+func (m *MQTT) tlsConfig() (*tls.Config, error) {
+	t := &tls.Config{InsecureSkipVerify: m.config.TLS.InsecureSkipVerify}
+	if m.config.TLS.CAFile != "" {
+		ca, err := ioutil.ReadFile(m.config.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		t.RootCAs = x509.NewCertPool()
+		if !t.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("mqtt: no certificates found in %s", m.config.TLS.CAFile)
+		}
+	}
+	if m.config.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(m.config.TLS.CertFile, m.config.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		t.Certificates = []tls.Certificate{cert}
+	}
+	return t, nil
+}
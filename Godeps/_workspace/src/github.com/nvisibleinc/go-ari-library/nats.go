@@ -1,6 +1,8 @@
 package ari
 
 import (
+	"context"
+	"fmt"
 	"github.com/apcera/nats"
 )
 
@@ -37,6 +39,14 @@ func (n *NATS) InitBus(config interface{}) error {
 	return nil
 }
 
+// Healthy returns an error unless the connection is up.
+func (n *NATS) Healthy(ctx context.Context) error {
+	if status := n.connection.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("nats: connection is not up (status %d)", status)
+	}
+	return nil
+}
+
 func (n *NATS) StartProducer(topic string) (chan []byte, error) {
 	c := make(chan []byte)
 	err := n.encoder.BindSendChan(topic, c)
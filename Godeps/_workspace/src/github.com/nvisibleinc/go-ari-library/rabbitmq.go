@@ -1,7 +1,10 @@
 package ari
 
 import (
+	"context"
+	"errors"
 	"github.com/streadway/amqp"
+	"sync"
 )
 
 type rabbitmqConfig struct {
@@ -11,6 +14,8 @@ type RabbitMQ struct {
 	config       rabbitmqConfig
 	producerConn *amqp.Connection
 	consumerConn *amqp.Connection
+	lock         sync.Mutex
+	closed       error // why a connection was closed, nil while both are open
 }
 
 func (r *RabbitMQ) InitBus(config interface{}) error {
@@ -31,9 +36,32 @@ func (r *RabbitMQ) InitBus(config interface{}) error {
 	if err != nil {
 		return err
 	}
+	r.watch(r.producerConn)
+	r.watch(r.consumerConn)
 	return nil
 }
 
+// watch records the error a connection is closed with.
+func (r *RabbitMQ) watch(conn *amqp.Connection) {
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	go func() {
+		var err error = errors.New("rabbitmq: connection closed")
+		if e := <-closed; e != nil {
+			err = e
+		}
+		r.lock.Lock()
+		r.closed = err
+		r.lock.Unlock()
+	}()
+}
+
+// Healthy returns the error a connection was closed with, if any.
+func (r *RabbitMQ) Healthy(ctx context.Context) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.closed
+}
+
 func (r *RabbitMQ) StartProducer(topic string) (chan []byte, error) {
 	c := make(chan []byte)
 	channel, err := r.producerConn.Channel()
@@ -47,19 +75,22 @@ func (r *RabbitMQ) StartProducer(topic string) (chan []byte, error) {
 
 	go func(channel *amqp.Channel, messages chan []byte) {
 		for message := range messages {
-			channel.Publish(
+			err := channel.Publish(
 				"", // exchange, for now always using the default exchange
 				topic,
 				false,
 				false,
 				amqp.Publishing{
 					Headers:         amqp.Table{},
-					ContentType:     "application/json",
+					ContentType:     ContentType(),
 					ContentEncoding: "",
 					Body:            message,
 					DeliveryMode:    amqp.Transient, // 1=non-persistent, 2=persistent
 					Priority:        0,              // 0-9
 				})
+			if err != nil {
+				publishFailed("rabbitmq", topic, err)
+			}
 		}
 	}(channel, c)
 	if err != nil {
@@ -91,6 +122,7 @@ func (r *RabbitMQ) StartConsumer(topic string) (chan []byte, error) {
 	}
 	go func(deliveries <-chan amqp.Delivery, c chan []byte) {
 		for d := range deliveries {
+			checkContentType(topic, d.ContentType)
 			c <- d.Body
 			d.Ack(false) // false does *not* mean don't acknowledge, see library docs for details
 		}
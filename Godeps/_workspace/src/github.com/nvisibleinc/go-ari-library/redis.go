@@ -0,0 +1,424 @@
+package ari
+
+import (
+	"context"
+	"fmt"
+	"github.com/gomodule/redigo/redis"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type redisConfig struct {
+	URL       string `json:"url"`
+	Group     string `json:"group"`
+	MaxLen    int    `json:"maxlen"`
+	DialogTTL int    `json:"dialog_ttl"`
+}
+
+// redisReadCount is the most messages read from a dialog stream at once.
+const redisReadCount = 64
+
+// Redis implements the MessageBus interface on top of Redis Streams. Every
+// topic is a stream read through a consumer group: on the application
+// signalling topic all application instances share the group, so AppStarts
+// are handed out round-robin, while the per-dialog streams have a single
+// reader. Dialog streams are trimmed to MaxLen entries on publish and expire
+// DialogTTL seconds after the topic is closed.
+//
+// The dialog streams a process consumes are all read by readDialogs with a
+// single blocking XREADGROUP, so the process holds one connection for them
+// however many dialogs it has. Besides that connection and one for each
+// consumed signalling topic, connections are only taken from the pool for
+// the duration of a command.
+type Redis struct {
+	config   redisConfig
+	pool     *redis.Pool
+	consumer string
+	lock     sync.Mutex
+	stop     map[string]chan bool
+	dialogs  map[string]*redisDialog // consumed dialog streams, by topic
+	readerID int64                   // client ID of the connection of readDialogs, 0 while it has none
+	wake     chan bool               // signalled when a dialog stream is consumed
+}
+
+// redisDialog queues the messages of a dialog stream for its consumer
+// channel until the topic is closed.
+type redisDialog struct {
+	queue chan []byte
+	done  chan bool
+}
+
+func (r *Redis) InitBus(config interface{}) error {
+	r.config = redisConfig{
+		URL:       "redis://localhost:6379",
+		Group:     "go-ari-library",
+		MaxLen:    1000,
+		DialogTTL: 60,
+	}
+	c := config.(map[string]interface{})
+	for key, value := range c {
+		switch key {
+		case "url":
+			r.config.URL = value.(string)
+		case "group":
+			r.config.Group = value.(string)
+		case "maxlen":
+			r.config.MaxLen = int(value.(float64))
+		case "dialog_ttl":
+			r.config.DialogTTL = int(value.(float64))
+		}
+	}
+
+	r.pool = &redis.Pool{
+		MaxIdle:     16,
+		IdleTimeout: 5 * time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.DialURL(r.config.URL)
+		},
+	}
+	r.stop = make(map[string]chan bool)
+	r.dialogs = make(map[string]*redisDialog)
+	r.wake = make(chan bool, 1)
+
+	// consumer names only have to be unique within a group
+	hostname, _ := os.Hostname()
+	r.consumer = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PING"); err != nil {
+		return err
+	}
+	go r.readDialogs()
+	return nil
+}
+
+// Healthy pings the Redis server.
+func (r *Redis) Healthy(ctx context.Context) error {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = redis.DoContext(conn, ctx, "PING")
+	return err
+}
+
+func (r *Redis) StartProducer(topic string) (chan []byte, error) {
+	if err := r.createGroup(topic); err != nil {
+		return nil, err
+	}
+	c := make(chan []byte)
+	go func(topic string, messages chan []byte) {
+		for message := range messages {
+			conn := r.pool.Get()
+			_, err := conn.Do("XADD", topic, "MAXLEN", "~", r.config.MaxLen, "*", "data", message, "content_type", ContentType())
+			conn.Close()
+			if err != nil {
+				publishFailed("redis", topic, err)
+			}
+		}
+	}(topic, c)
+	return c, nil
+}
+
+func (r *Redis) StartConsumer(topic string) (chan []byte, error) {
+	if err := r.createGroup(topic); err != nil {
+		return nil, err
+	}
+	c := make(chan []byte)
+	if isDialogTopic(topic) {
+		r.addDialog(topic, c)
+		return c, nil
+	}
+	stop := make(chan bool)
+	r.lock.Lock()
+	r.stop[topic] = stop
+	r.lock.Unlock()
+	go r.consume(topic, c, stop)
+	return c, nil
+}
+
+// TopicExists reports whether a consumer group has been set up on the topic,
+// which happens as soon as either side of the topic starts using it.
+func (r *Redis) TopicExists(topic string) bool {
+	conn := r.pool.Get()
+	defer conn.Close()
+	groups, err := redis.Values(conn.Do("XINFO", "GROUPS", topic))
+	return err == nil && len(groups) > 0
+}
+
+// CloseTopic stops the local consumer of a topic and lets the stream expire
+// after the configured dialog TTL, so late messages can still be read.
+func (r *Redis) CloseTopic(topic string) error {
+	r.lock.Lock()
+	if stop, ok := r.stop[topic]; ok {
+		close(stop)
+		delete(r.stop, topic)
+	}
+	if d, ok := r.dialogs[topic]; ok {
+		close(d.done)
+		delete(r.dialogs, topic)
+	}
+	r.lock.Unlock()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("EXPIRE", topic, r.config.DialogTTL)
+	return err
+}
+
+// createGroup creates the stream and its consumer group if they do not exist
+// yet. A new group on a dialog stream starts at the beginning of the stream
+// so nothing published before the reader attached is lost; on a signalling
+// stream it starts at the end, as old AppStarts are of no use to anyone.
+func (r *Redis) createGroup(topic string) error {
+	start := "$"
+	if isDialogTopic(topic) {
+		start = "0"
+	}
+	conn := r.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("XGROUP", "CREATE", topic, r.config.Group, start, "MKSTREAM")
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// consume reads a signalling topic through its consumer group until the
+// topic is closed, acknowledging every message once it is handed to the
+// channel.
+func (r *Redis) consume(topic string, c chan []byte, stop chan bool) {
+	conn := r.pool.Get()
+	defer conn.Close()
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		reply, err := redis.Values(conn.Do("XREADGROUP", "GROUP", r.config.Group, r.consumer,
+			"COUNT", 1, "BLOCK", 1000, "STREAMS", topic, ">"))
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil && strings.HasPrefix(err.Error(), "NOGROUP") {
+			// the stream expired after the other side closed the topic
+			close(c)
+			return
+		}
+		if err != nil {
+			logger.Error("unable to read message", "bus", "redis", LogTopic, topic, LogError, err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range reply {
+			_, entries := redisEntries(stream)
+			for _, entry := range entries {
+				if entry.data != nil {
+					select {
+					case c <- entry.data:
+					case <-stop:
+						return
+					}
+				}
+				conn.Do("XACK", topic, r.config.Group, entry.id)
+			}
+		}
+	}
+}
+
+// addDialog hands a dialog stream to readDialogs. The reader is woken up
+// from the read it is blocked in, so that the stream is read at once rather
+// than once that read times out.
+func (r *Redis) addDialog(topic string, c chan []byte) {
+	d := &redisDialog{queue: make(chan []byte, 4*redisReadCount), done: make(chan bool)}
+	go d.deliver(c)
+	r.lock.Lock()
+	r.dialogs[topic] = d
+	readerID := r.readerID
+	r.lock.Unlock()
+
+	select {
+	case r.wake <- true:
+	default:
+	}
+	if readerID != 0 {
+		conn := r.pool.Get()
+		defer conn.Close()
+		conn.Do("CLIENT", "UNBLOCK", readerID)
+	}
+}
+
+// deliver hands the queued messages of a dialog to its consumer channel
+// until the topic is closed, and closes the channel once the stream has
+// expired.
+func (d *redisDialog) deliver(c chan []byte) {
+	for {
+		select {
+		case message, ok := <-d.queue:
+			if !ok {
+				close(c)
+				return
+			}
+			select {
+			case c <- message:
+			case <-d.done:
+				return
+			}
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// readDialogs reads every consumed dialog stream with a single blocking
+// XREADGROUP and queues the messages for their consumers, acknowledging them
+// once they are queued. Streams whose queue cannot take a full read are left
+// out until their consumer catches up, so that a slow consumer neither holds
+// up the other dialogs nor loses messages.
+func (r *Redis) readDialogs() {
+	var conn redis.Conn
+	for {
+		if conn == nil {
+			conn = r.pool.Get()
+			id, err := redis.Int64(conn.Do("CLIENT", "ID"))
+			if err != nil {
+				logger.Error("unable to connect the dialog reader", "bus", "redis", LogError, err)
+				conn.Close()
+				conn = nil
+				time.Sleep(time.Second)
+				continue
+			}
+			r.lock.Lock()
+			r.readerID = id
+			r.lock.Unlock()
+		}
+
+		topics := r.readableDialogs()
+		if len(topics) == 0 {
+			select {
+			case <-r.wake:
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		args := []interface{}{"GROUP", r.config.Group, r.consumer, "COUNT", redisReadCount, "BLOCK", 1000, "STREAMS"}
+		for _, topic := range topics {
+			args = append(args, topic)
+		}
+		for range topics {
+			args = append(args, ">")
+		}
+		reply, err := redis.Values(conn.Do("XREADGROUP", args...))
+		if err == redis.ErrNil {
+			// timed out, or woken up for a new dialog
+			continue
+		}
+		if err != nil && strings.HasPrefix(err.Error(), "NOGROUP") {
+			// a stream expired after the other side closed the topic
+			r.expireDialogs(topics)
+			continue
+		}
+		if err != nil {
+			logger.Error("unable to read messages", "bus", "redis", LogError, err)
+			if conn.Err() != nil {
+				r.lock.Lock()
+				r.readerID = 0
+				r.lock.Unlock()
+				conn.Close()
+				conn = nil
+			}
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range reply {
+			topic, entries := redisEntries(stream)
+			r.lock.Lock()
+			d, ok := r.dialogs[topic]
+			r.lock.Unlock()
+			if !ok {
+				// closed while it was read
+				continue
+			}
+			ack := []interface{}{topic, r.config.Group}
+			for _, entry := range entries {
+				if entry.data != nil {
+					d.queue <- entry.data
+				}
+				ack = append(ack, entry.id)
+			}
+			conn.Do("XACK", ack...)
+		}
+	}
+}
+
+// readableDialogs lists the dialog streams whose queue can take a full read.
+// Only readDialogs adds to the queues, so they keep that room until it reads
+// again.
+func (r *Redis) readableDialogs() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var topics []string
+	for topic, d := range r.dialogs {
+		if cap(d.queue)-len(d.queue) >= redisReadCount {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// expireDialogs ends the consumers of the dialog streams among topics whose
+// consumer group is gone, once the messages queued for them are delivered.
+func (r *Redis) expireDialogs(topics []string) {
+	for _, topic := range topics {
+		if r.TopicExists(topic) {
+			continue
+		}
+		r.lock.Lock()
+		d, ok := r.dialogs[topic]
+		delete(r.dialogs, topic)
+		r.lock.Unlock()
+		if ok {
+			close(d.queue)
+		}
+	}
+}
+
+// redisEntry is a message read from a stream.
+type redisEntry struct {
+	id   string
+	data []byte
+}
+
+// redisEntries returns the name and the messages of a stream in the reply to
+// XREADGROUP, checking the content type of each message.
+func redisEntries(stream interface{}) (string, []redisEntry) {
+	// each stream is [name, [[id, [field, value, ...]], ...]]
+	s, _ := redis.Values(stream, nil)
+	topic, _ := redis.String(s[0], nil)
+	values, _ := redis.Values(s[1], nil)
+	entries := make([]redisEntry, 0, len(values))
+	for _, value := range values {
+		e, _ := redis.Values(value, nil)
+		var entry redisEntry
+		entry.id, _ = redis.String(e[0], nil)
+		fields, _ := redis.ByteSlices(e[1], nil)
+		for i := 0; i+1 < len(fields); i += 2 {
+			switch string(fields[i]) {
+			case "data":
+				entry.data = fields[i+1]
+			case "content_type":
+				checkContentType(topic, string(fields[i+1]))
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return topic, entries
+}
@@ -0,0 +1,66 @@
+package ari
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRedis connects to the server at REDIS_URL and skips the test when
+// it is not set. Every test reads through a consumer group of its own.
+func newTestRedis(t *testing.T) *Redis {
+	t.Helper()
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		t.Skip("REDIS_URL is not set")
+	}
+	r := new(Redis)
+	err := r.InitBus(map[string]interface{}{
+		"url":   url,
+		"group": strings.Join([]string{"test-", UUID()}, ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRedisDialogsShareConnection(t *testing.T) {
+	r := newTestRedis(t)
+	const dialogs = 100
+	topics := make([]string, dialogs)
+	consumers := make([]chan []byte, dialogs)
+	producers := make([]chan []byte, dialogs)
+	for i := range topics {
+		topics[i] = strings.Join([]string{"events_", UUID()}, "")
+		var err error
+		if consumers[i], err = r.StartConsumer(topics[i]); err != nil {
+			t.Fatal(err)
+		}
+		if producers[i], err = r.StartProducer(topics[i]); err != nil {
+			t.Fatal(err)
+		}
+		defer r.CloseTopic(topics[i])
+	}
+
+	for i, topic := range topics {
+		producers[i] <- []byte(topic)
+	}
+	for i, topic := range topics {
+		select {
+		case m := <-consumers[i]:
+			if string(m) != topic {
+				t.Errorf("%s received %q", topic, m)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s received nothing", topic)
+		}
+	}
+
+	// the dialogs are read through the connection of the shared reader only
+	stats := r.pool.Stats()
+	if inUse := stats.ActiveCount - stats.IdleCount; inUse > 1 {
+		t.Errorf("%d connections are in use for %d dialogs", inUse, dialogs)
+	}
+}
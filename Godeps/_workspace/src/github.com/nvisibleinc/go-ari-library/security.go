@@ -0,0 +1,195 @@
+package ari
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// global variables
+var appKeys = make(map[string]*Keys) // keys of the applications served by this process, by name
+var appKeysLock sync.RWMutex
+
+// Keys holds the keys the messages of an application are signed and
+// encrypted with. Every key has an ID which travels in the envelope, so that
+// a new key can be distributed alongside the old one before senders switch
+// to it, and the old one removed once no message uses it any more.
+type Keys struct {
+	SigningKeyID      string                        // key Commands are signed with, unsigned when empty
+	HMACKeys          map[string][]byte             // HMAC-SHA256 shared secrets by key ID
+	Ed25519Keys       map[string]ed25519.PrivateKey // Ed25519 signing keys by key ID
+	Ed25519PublicKeys map[string]ed25519.PublicKey  // Ed25519 verification keys by key ID
+	EncryptionKeyID   string                        // key Events are encrypted with, in clear when empty
+	AESKeys           map[string][]byte             // AES-GCM keys by key ID
+}
+
+// SetKeys sets the keys of an application. The library signs the Commands of
+// the application's instances and decrypts their Events with them.
+func SetKeys(application string, k *Keys) {
+	appKeysLock.Lock()
+	defer appKeysLock.Unlock()
+	appKeys[application] = k
+}
+
+// keysFor returns the keys of an application, or nil.
+func keysFor(application string) *Keys {
+	appKeysLock.RLock()
+	defer appKeysLock.RUnlock()
+	return appKeys[application]
+}
+
+// Sign signs a Command with the signing key. Its envelope must be complete,
+// as the signature covers it.
+func (c *Command) Sign(k *Keys) error {
+	if k == nil || k.SigningKeyID == "" {
+		return nil
+	}
+	c.KeyID = k.SigningKeyID
+	if secret, ok := k.HMACKeys[c.KeyID]; ok {
+		c.Signature = hmacSum(secret, c.signedBytes())
+		return nil
+	}
+	if key, ok := k.Ed25519Keys[c.KeyID]; ok {
+		c.Signature = ed25519.Sign(key, c.signedBytes())
+		return nil
+	}
+	return fmt.Errorf("unknown signing key %q", c.KeyID)
+}
+
+// Verify checks the signature of a Command. Commands need a valid signature
+// as soon as any HMAC or Ed25519 verification key is set.
+func (c *Command) Verify(k *Keys) error {
+	if k == nil || len(k.HMACKeys)+len(k.Ed25519PublicKeys) == 0 {
+		return nil
+	}
+	if len(c.Signature) == 0 {
+		return errors.New("command is not signed")
+	}
+	if secret, ok := k.HMACKeys[c.KeyID]; ok {
+		if !hmac.Equal(c.Signature, hmacSum(secret, c.signedBytes())) {
+			return errors.New("invalid command signature")
+		}
+		return nil
+	}
+	if key, ok := k.Ed25519PublicKeys[c.KeyID]; ok {
+		if !ed25519.Verify(key, c.signedBytes(), c.Signature) {
+			return errors.New("invalid command signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown signing key %q", c.KeyID)
+}
+
+// Encrypt encrypts the ARI body, or the compressed payload, of an Event with
+// the encryption key. Its envelope must be complete, as it is authenticated
+// along with the payload.
+func (e *Event) Encrypt(k *Keys) error {
+	if k == nil || k.EncryptionKeyID == "" {
+		return nil
+	}
+	gcm, err := k.gcm(k.EncryptionKeyID)
+	if err != nil {
+		return err
+	}
+	plaintext := []byte(e.ARI_Body)
+	if e.Compression != "" {
+		plaintext = e.Payload
+	}
+	e.Encryption, e.KeyID = "aes-gcm", k.EncryptionKeyID
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	e.Payload, e.ARI_Body = gcm.Seal(nonce, nonce, plaintext, e.authenticatedBytes()), nil
+	return nil
+}
+
+// Decrypt restores an encrypted Event. Events must be encrypted as soon as
+// any AES key is set.
+func (e *Event) Decrypt(k *Keys) error {
+	if e.Encryption == "" {
+		if k != nil && len(k.AESKeys) > 0 {
+			return errors.New("event is not encrypted")
+		}
+		return nil
+	}
+	if e.Encryption != "aes-gcm" {
+		return fmt.Errorf("unknown encryption %q", e.Encryption)
+	}
+	if k == nil {
+		return errors.New("no keys to decrypt the event")
+	}
+	gcm, err := k.gcm(e.KeyID)
+	if err != nil {
+		return err
+	}
+	if len(e.Payload) < gcm.NonceSize() {
+		return errors.New("encrypted payload is too short")
+	}
+	nonce, ciphertext := e.Payload[:gcm.NonceSize()], e.Payload[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, e.authenticatedBytes())
+	if err != nil {
+		return err
+	}
+	e.Encryption, e.KeyID = "", ""
+	if e.Compression != "" {
+		e.Payload = plaintext
+	} else {
+		e.Payload, e.ARI_Body = nil, json.RawMessage(plaintext)
+	}
+	return nil
+}
+
+// gcm returns the AES-GCM cipher of a key.
+func (k *Keys) gcm(keyID string) (cipher.AEAD, error) {
+	key, ok := k.AESKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %q", keyID)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// signedBytes returns the fields of a Command covered by its signature. The
+// proxy instance ID is covered as the numbering of commands starts over
+// when it changes.
+func (c *Command) signedBytes() []byte {
+	return canonical(strconv.Itoa(c.Version), c.DialogID, c.Application, strconv.FormatUint(c.Sequence, 10), c.KeyID,
+		c.ProxyInstanceID, c.UniqueID, c.Method, c.URL, c.Body)
+}
+
+// authenticatedBytes returns the fields of an Event authenticated along with
+// its encrypted payload, which bind the payload to its place in the dialog.
+func (e *Event) authenticatedBytes() []byte {
+	return canonical(strconv.Itoa(e.Version), e.DialogID, e.Application, strconv.FormatUint(e.Sequence, 10), e.KeyID,
+		e.ProxyInstanceID, e.Compression, e.Type)
+}
+
+// canonical joins fields unambiguously by prefixing each with its length.
+func canonical(fields ...string) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = binary.AppendUvarint(b, uint64(len(f)))
+		b = append(b, f...)
+	}
+	return b
+}
+
+// hmacSum returns the HMAC-SHA256 of a message.
+func hmacSum(secret []byte, message []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
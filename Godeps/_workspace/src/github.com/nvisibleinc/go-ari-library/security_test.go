@@ -0,0 +1,36 @@
+package ari
+
+import (
+	"testing"
+)
+
+func TestCommandReplay(t *testing.T) {
+	keys := &Keys{SigningKeyID: "1", HMACKeys: map[string][]byte{"1": []byte("secret")}}
+	c := Command{
+		Envelope: Envelope{Version: EnvelopeVersion, DialogID: "dialog", Application: "app", Sequence: 1},
+		UniqueID: "1", URL: "/channels/1/answer", Method: "POST",
+	}
+	if err := c.Sign(keys); err != nil {
+		t.Fatal(err)
+	}
+	check := NewSequenceCheck("dialog", "commands_dialog")
+	if err := c.Verify(keys); err != nil || !check.Accept(c.Envelope) {
+		t.Fatalf("the signed command was refused: %v", err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		change func(c *Command)
+	}{
+		{"as is", func(c *Command) {}},
+		{"with another proxy instance ID", func(c *Command) { c.ProxyInstanceID = "other" }},
+		{"with another sequence number", func(c *Command) { c.Sequence = 2 }},
+		{"in another dialog", func(c *Command) { c.DialogID = "other" }},
+	} {
+		replayed := c
+		test.change(&replayed)
+		if replayed.Verify(keys) == nil && check.Accept(replayed.Envelope) {
+			t.Errorf("the command replayed %s was accepted", test.name)
+		}
+	}
+}
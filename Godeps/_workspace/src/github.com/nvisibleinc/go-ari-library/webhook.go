@@ -0,0 +1,397 @@
+package ari
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webhookMaxAge is how old the timestamp of a signed request may be, or how
+// far in the future, before the request is refused as a replay.
+const webhookMaxAge = 5 * time.Minute
+
+// webhookMaxCommand is the largest command request body accepted, in bytes.
+const webhookMaxCommand = 1 << 20
+
+type webhookConfig struct {
+	Listen         string                        `json:"listen"`
+	Applications   map[string]webhookApplication `json:"applications"`
+	MaxRetries     int                           `json:"max_retries"`
+	RetryBackoff   int                           `json:"retry_backoff_ms"`
+	CommandTimeout int                           `json:"command_timeout_ms"`
+}
+
+// webhookApplication holds the endpoint and shared secret of an application.
+type webhookApplication struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// Webhook implements the MessageBus interface for applications that are HTTP
+// handlers rather than bus subscribers. AppStarts and dialog events are
+// POSTed to the application's URL, signed with an HMAC-SHA256 of a timestamp
+// and the body. Applications send Commands back by POSTing them, signed the
+// same way, to /commands/<dialogID> on the listen address; the
+// CommandResponse is returned as the body of that HTTP response.
+type Webhook struct {
+	config     webhookConfig
+	client     *http.Client
+	lock       sync.RWMutex
+	dialogApps map[string]string                        // dialog ID to application, learned from AppStarts
+	commands   map[string]chan []byte                   // dialog ID to command consumer
+	pending    map[pendingCommand]chan *CommandResponse // command to waiting HTTP request
+}
+
+// pendingCommand identifies a command waiting for its response. Unique IDs
+// are chosen by the applications, so they are only unique within a dialog.
+type pendingCommand struct {
+	dialogID string
+	uniqueID string
+}
+
+func (w *Webhook) InitBus(config interface{}) error {
+	w.config = webhookConfig{
+		Listen:         ":8090",
+		MaxRetries:     5,
+		RetryBackoff:   200,
+		CommandTimeout: 10000,
+	}
+	// round trip through json to fill in the nested structs
+	j, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(j, &w.config); err != nil {
+		return err
+	}
+	if len(w.config.Applications) == 0 {
+		return fmt.Errorf("webhook: no applications configured")
+	}
+
+	w.client = &http.Client{Timeout: 10 * time.Second}
+	w.dialogApps = make(map[string]string)
+	w.commands = make(map[string]chan []byte)
+	w.pending = make(map[pendingCommand]chan *CommandResponse)
+
+	// listen before returning, so that an address in use fails InitBus
+	l, err := net.Listen("tcp", w.config.Listen)
+	if err != nil {
+		return fmt.Errorf("webhook: %s", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/commands/", w.handleCommand)
+	go func() {
+		err := http.Serve(l, mux)
+		logger.Error("unable to serve commands", "bus", "webhook", LogError, err)
+	}()
+	return nil
+}
+
+func (w *Webhook) StartProducer(topic string) (chan []byte, error) {
+	c := make(chan []byte)
+	if strings.HasPrefix(topic, "responses_") {
+		_, dialogID := splitDialogTopic(topic)
+		go w.deliverResponses(dialogID, c)
+		return c, nil
+	}
+
+	go func(topic string, messages chan []byte) {
+		// every topic has its own goroutine and a message is only dropped
+		// once all retries are exhausted, which keeps a dialog's events in order
+		for message := range messages {
+			var app, dialogID string
+			if isDialogTopic(topic) {
+				_, dialogID = splitDialogTopic(topic)
+				w.lock.RLock()
+				app = w.dialogApps[dialogID]
+				w.lock.RUnlock()
+			} else {
+				var as AppStart
+				Unmarshal(message, &as)
+				app, dialogID = topic, as.DialogID
+				w.lock.Lock()
+				w.dialogApps[dialogID] = app
+				w.lock.Unlock()
+			}
+			if strings.HasPrefix(topic, "events_") {
+				message = decompressedEvent(message)
+			}
+			if err := w.post(app, topic, dialogID, message); err != nil {
+				publishFailed("webhook", topic, err)
+			}
+		}
+	}(topic, c)
+	return c, nil
+}
+
+func (w *Webhook) StartConsumer(topic string) (chan []byte, error) {
+	if !strings.HasPrefix(topic, "commands_") {
+		return nil, fmt.Errorf("webhook: cannot consume %s, only commands are received", topic)
+	}
+	_, dialogID := splitDialogTopic(topic)
+	c := make(chan []byte)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.commands[dialogID] = c
+	return c, nil
+}
+
+// TopicExists always returns true, as applications reach the proxy on demand.
+func (w *Webhook) TopicExists(topic string) bool {
+	return true
+}
+
+// CloseTopic stops accepting commands for a dialog and forgets its
+// application.
+func (w *Webhook) CloseTopic(topic string) error {
+	if !strings.HasPrefix(topic, "commands_") {
+		return nil
+	}
+	_, dialogID := splitDialogTopic(topic)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.commands, dialogID)
+	delete(w.dialogApps, dialogID)
+	return nil
+}
+
+// decompressedEvent restores the ARI body of a compressed event, as webhook
+// receivers are plain HTTP handlers that cannot be expected to decompress
+// it. Encrypted events are left as they are.
+func decompressedEvent(message []byte) []byte {
+	var e Event
+	if Unmarshal(message, &e) != nil || e.Compression == "" || e.Encryption != "" {
+		return message
+	}
+	if err := e.Decompress(); err != nil {
+		logger.Warn("unable to decompress event", "bus", "webhook", LogDialogID, e.DialogID, LogError, err)
+		return message
+	}
+	decompressed, err := Marshal(&e)
+	if err != nil {
+		return message
+	}
+	return decompressed
+}
+
+// post delivers a message to the application's URL, retrying with an
+// exponential backoff on transport errors and non-2xx responses.
+func (w *Webhook) post(app string, topic string, dialogID string, message []byte) error {
+	a, ok := w.config.Applications[app]
+	if !ok {
+		return fmt.Errorf("no webhook configured for application %q", app)
+	}
+	kind := "app_start"
+	if isDialogTopic(topic) {
+		kind, _ = splitDialogTopic(topic)
+	}
+
+	backoff := time.Duration(w.config.RetryBackoff) * time.Millisecond
+	var err error
+	for attempt := 0; attempt <= w.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		var req *http.Request
+		req, err = http.NewRequest("POST", a.URL, bytes.NewReader(message))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", ContentType())
+		req.Header.Set("X-Ari-Application", app)
+		req.Header.Set("X-Ari-Dialog-Id", dialogID)
+		req.Header.Set("X-Ari-Message-Type", kind)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Ari-Timestamp", timestamp)
+		req.Header.Set("X-Ari-Signature", sign(a.Secret, timestamp, message))
+
+		var res *http.Response
+		res, err = w.client.Do(req)
+		if err != nil {
+			continue
+		}
+		res.Body.Close()
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("webhook returned %s", res.Status)
+	}
+	return err
+}
+
+// handleCommand accepts a signed Command for a dialog, hands it to the
+// dialog's command consumer and waits for the matching CommandResponse.
+// Commands may arrive as soon as the application has its AppStart, before
+// the proxy has started consuming the dialog's commands, so they wait for
+// the consumer up to the command timeout.
+func (w *Webhook) handleCommand(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	dialogID := strings.TrimPrefix(r.URL.Path, "/commands/")
+	w.lock.RLock()
+	a, known := w.config.Applications[w.dialogApps[dialogID]]
+	w.lock.RUnlock()
+	if !known {
+		http.Error(rw, "unknown dialog", http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, webhookMaxCommand))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(rw, err.Error(), status)
+		return
+	}
+	if err = verify(a.Secret, r.Header.Get("X-Ari-Timestamp"), r.Header.Get("X-Ari-Signature"), body); err != nil {
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" && ct != ContentType() {
+		http.Error(rw, "commands must be "+ContentType(), http.StatusUnsupportedMediaType)
+		return
+	}
+	var c Command
+	if err = Unmarshal(body, &c); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if c.UniqueID == "" {
+		c.UniqueID = UUID()
+	}
+	command, _ := Marshal(c)
+
+	response := make(chan *CommandResponse, 1)
+	key := pendingCommand{dialogID: dialogID, uniqueID: c.UniqueID}
+	w.lock.Lock()
+	if _, ok := w.pending[key]; ok {
+		w.lock.Unlock()
+		http.Error(rw, "a command with this unique ID is pending", http.StatusConflict)
+		return
+	}
+	w.pending[key] = response
+	w.lock.Unlock()
+	defer func() {
+		w.lock.Lock()
+		delete(w.pending, key)
+		w.lock.Unlock()
+	}()
+
+	deadline := time.Now().Add(time.Duration(w.config.CommandTimeout) * time.Millisecond)
+	commands := w.commandConsumer(dialogID, deadline)
+	if commands == nil {
+		http.Error(rw, "dialog is not accepting commands", http.StatusServiceUnavailable)
+		return
+	}
+	timeout := time.After(time.Until(deadline))
+	select {
+	case commands <- command:
+	case <-timeout:
+		http.Error(rw, "dialog is not accepting commands", http.StatusServiceUnavailable)
+		return
+	}
+	select {
+	case cr := <-response:
+		response, _ := Marshal(cr)
+		rw.Header().Set("Content-Type", ContentType())
+		rw.Write(response)
+	case <-timeout:
+		http.Error(rw, "timed out waiting for a response", http.StatusGatewayTimeout)
+	}
+}
+
+// deliverResponses matches the CommandResponses of a dialog to the HTTP
+// requests of the dialog waiting for them.
+func (w *Webhook) deliverResponses(dialogID string, responses chan []byte) {
+	for response := range responses {
+		var cr CommandResponse
+		if Unmarshal(response, &cr) != nil {
+			continue
+		}
+		if err := cr.Decompress(); err != nil {
+			logger.Warn("unable to decompress response", "bus", "webhook", LogUniqueID, cr.UniqueID, LogError, err)
+			continue
+		}
+		if cr.DialogID != "" && cr.DialogID != dialogID {
+			logger.Warn("dropping misrouted response", "bus", "webhook", LogDialogID, cr.DialogID, LogUniqueID, cr.UniqueID)
+			continue
+		}
+		w.lock.RLock()
+		waiting, ok := w.pending[pendingCommand{dialogID: dialogID, uniqueID: cr.UniqueID}]
+		w.lock.RUnlock()
+		if !ok {
+			logger.Debug("dropping response nobody waits for", "bus", "webhook", LogUniqueID, cr.UniqueID)
+			continue
+		}
+		select {
+		case waiting <- &cr:
+		default:
+			// the request already has its response
+			logger.Debug("dropping duplicate response", "bus", "webhook", LogUniqueID, cr.UniqueID)
+		}
+	}
+}
+
+// commandConsumer returns the command consumer of a dialog, waiting for the
+// proxy to start it until the deadline. It returns nil if it did not start,
+// or once the dialog has ended.
+func (w *Webhook) commandConsumer(dialogID string, deadline time.Time) chan []byte {
+	for {
+		w.lock.RLock()
+		commands, ok := w.commands[dialogID]
+		_, known := w.dialogApps[dialogID]
+		w.lock.RUnlock()
+		if ok {
+			return commands
+		}
+		if !known || time.Now().After(deadline) {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// sign returns the signature header value for a timestamp and a body: the
+// hex encoded HMAC-SHA256 of the timestamp, a dot and the body, keyed with
+// the application's secret.
+func sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature of a request, and that its timestamp, in
+// seconds since the epoch, is recent enough for the request not to be a
+// replay.
+func verify(secret string, timestamp string, signature string, body []byte) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid timestamp")
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > webhookMaxAge || age < -webhookMaxAge {
+		return errors.New("stale timestamp")
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(secret, timestamp, body))) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package ari
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestWebhook returns a webhook bus with a single application, "app",
+// without serving the command endpoint.
+func newTestWebhook() *Webhook {
+	return &Webhook{
+		config: webhookConfig{
+			Applications:   map[string]webhookApplication{"app": {URL: "http://127.0.0.1/", Secret: "secret"}},
+			CommandTimeout: 1000,
+		},
+		dialogApps: make(map[string]string),
+		commands:   make(map[string]chan []byte),
+		pending:    make(map[pendingCommand]chan *CommandResponse),
+	}
+}
+
+// commandRequest returns a command request for a dialog, signed at the given
+// time.
+func commandRequest(dialogID string, c Command, at time.Time) *http.Request {
+	body, _ := Marshal(c)
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	r := httptest.NewRequest("POST", "/commands/"+dialogID, bytes.NewReader(body))
+	r.Header.Set("X-Ari-Timestamp", timestamp)
+	r.Header.Set("X-Ari-Signature", sign("secret", timestamp, body))
+	return r
+}
+
+func TestWebhookVerify(t *testing.T) {
+	body := []byte(`{"url":"/channels"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-webhookMaxAge-time.Minute).Unix(), 10)
+	for _, test := range []struct {
+		name      string
+		timestamp string
+		signature string
+		valid     bool
+	}{
+		{"valid", now, sign("secret", now, body), true},
+		{"stale", stale, sign("secret", stale, body), false},
+		{"replayed with a new timestamp", now, sign("secret", stale, body), false},
+		{"wrong secret", now, sign("other", now, body), false},
+		{"missing timestamp", "", sign("secret", "", body), false},
+	} {
+		if err := verify("secret", test.timestamp, test.signature, body); (err == nil) != test.valid {
+			t.Errorf("%s: verify returned %v", test.name, err)
+		}
+	}
+}
+
+func TestWebhookCommandBeforeConsumer(t *testing.T) {
+	w := newTestWebhook()
+	w.dialogApps["dialog"] = "app"
+	responses, _ := w.StartProducer("responses_dialog")
+
+	rec := httptest.NewRecorder()
+	done := make(chan bool)
+	go func() {
+		w.handleCommand(rec, commandRequest("dialog", Command{UniqueID: "1", URL: "/channels", Method: "GET"}, time.Now()))
+		close(done)
+	}()
+
+	// the proxy starts consuming the dialog's commands after the application
+	// has sent one
+	time.Sleep(50 * time.Millisecond)
+	commands, err := w.StartConsumer("commands_dialog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Command
+	Unmarshal(<-commands, &c)
+	r, _ := Marshal(CommandResponse{UniqueID: c.UniqueID, StatusCode: 200})
+	responses <- r
+	// a duplicate response must not hold up the others
+	responses <- r
+	<-done
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status is %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	w.handleCommand(rec, commandRequest("dialog", Command{UniqueID: "2"}, time.Now().Add(-time.Hour)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("a stale command got status %d, want 401", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	w.handleCommand(rec, commandRequest("unknown", Command{UniqueID: "3"}, time.Now()))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("a command of an unknown dialog got status %d, want 404", rec.Code)
+	}
+}
+
+func TestWebhookDecompresses(t *testing.T) {
+	if err := SetCompression("gzip", 1); err != nil {
+		t.Fatal(err)
+	}
+	defer SetCompression("", 0)
+	posted := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		posted <- body
+	}))
+	defer server.Close()
+	w := newTestWebhook()
+	w.client = server.Client()
+	w.config.Applications["app"] = webhookApplication{URL: server.URL, Secret: "secret"}
+	w.dialogApps["dialog"] = "app"
+
+	body := json.RawMessage(`{"type":"StasisStart"}`)
+	e := Event{Type: "StasisStart", ARI_Body: body}
+	if err := e.Compress(); err != nil || e.Compression != "gzip" {
+		t.Fatalf("the event was not compressed: %v", err)
+	}
+	message, _ := Marshal(&e)
+	events, _ := w.StartProducer("events_dialog")
+	events <- message
+	select {
+	case m := <-posted:
+		var received Event
+		Unmarshal(m, &received)
+		if received.Compression != "" || string(received.ARI_Body) != string(body) {
+			t.Errorf("posted %s, want the ARI body uncompressed", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the event was not posted")
+	}
+
+	waiting := make(chan *CommandResponse, 1)
+	w.pending[pendingCommand{dialogID: "dialog", uniqueID: "1"}] = waiting
+	r := CommandResponse{UniqueID: "1", StatusCode: 200, ResponseBody: `[{"id":"channel"}]`}
+	r.Compress()
+	message, _ = Marshal(&r)
+	responses, _ := w.StartProducer("responses_dialog")
+	responses <- message
+	if cr := <-waiting; cr.Compression != "" || cr.ResponseBody != `[{"id":"channel"}]` {
+		t.Errorf("delivered %+v, want the response body uncompressed", cr)
+	}
+}
+
+func TestWebhookListenInUse(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	config := map[string]interface{}{
+		"listen":       l.Addr().String(),
+		"applications": map[string]interface{}{"app": map[string]interface{}{"url": "http://127.0.0.1/", "secret": "secret"}},
+	}
+	if err = new(Webhook).InitBus(config); err == nil {
+		t.Error("the webhook bus started on an address in use")
+	}
+}
+
+func TestWebhookResponsesByDialog(t *testing.T) {
+	w := newTestWebhook()
+	// two dialogs whose commands have the same unique ID
+	first := make(chan *CommandResponse, 1)
+	second := make(chan *CommandResponse, 1)
+	w.pending[pendingCommand{dialogID: "first", uniqueID: "1"}] = first
+	w.pending[pendingCommand{dialogID: "second", uniqueID: "1"}] = second
+	responses, _ := w.StartProducer("responses_second")
+	for _, r := range []CommandResponse{
+		{Envelope: Envelope{DialogID: "first"}, UniqueID: "1", StatusCode: 404},
+		{Envelope: Envelope{DialogID: "second"}, UniqueID: "1", StatusCode: 200},
+	} {
+		message, _ := Marshal(&r)
+		responses <- message
+	}
+	select {
+	case cr := <-second:
+		if cr.StatusCode != 200 {
+			t.Errorf("the second dialog got status %d, want 200", cr.StatusCode)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the response was not delivered")
+	}
+	select {
+	case cr := <-first:
+		t.Errorf("the first dialog got the response %+v of another dialog", cr)
+	default:
+	}
+}
+
+func TestWebhookCommandTooLarge(t *testing.T) {
+	w := newTestWebhook()
+	w.dialogApps["dialog"] = "app"
+	rec := httptest.NewRecorder()
+	w.handleCommand(rec, commandRequest("dialog", Command{UniqueID: "1", Body: strings.Repeat("x", webhookMaxCommand)}, time.Now()))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("a command too large got status %d, want 413", rec.Code)
+	}
+}
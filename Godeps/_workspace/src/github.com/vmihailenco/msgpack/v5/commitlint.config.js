@@ -0,0 +1 @@
+module.exports = { extends: ['@commitlint/config-conventional'] }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"github.com/nvisibleinc/go-ari-library"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// dialogSummary describes an active dialog in the admin API.
+type dialogSummary struct {
+	DialogID    string    `json:"dialog_id"`
+	Application string    `json:"application"`
+	Objects     []string  `json:"objects"` // ARI objects owned by the dialog
+	Started     time.Time `json:"started"`
+	Age         float64   `json:"age_seconds"`
+}
+
+// dialogDetail describes a single dialog and the last events published to
+// it in the admin API.
+type dialogDetail struct {
+	dialogSummary
+	EventSequence    uint64      `json:"event_sequence"`
+	ResponseSequence uint64      `json:"response_sequence"`
+	RecentEvents     []ari.Event `json:"recent_events"`
+}
+
+// runAdmin serves the admin API until the listener fails.
+func runAdmin() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dialogs", listDialogs)
+	mux.HandleFunc("/dialogs/", routeDialog)
+	mux.HandleFunc("/logging/level", serveLogLevel)
+	logger.Info("starting admin API", "listen", config.Admin.Listen)
+	err := http.ListenAndServe(config.Admin.Listen, requireAdmin(mux))
+	fatal("unable to serve the admin API", ari.LogError, err)
+}
+
+// requireAdmin only passes on requests carrying one of the admin tokens as a
+// bearer token.
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		for _, admin := range config.Admin.Tokens {
+			if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(admin)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "admin token required"})
+	})
+}
+
+// routeDialog routes the requests on /dialogs/<id> and
+// /dialogs/<id>/objects/<object>.
+func routeDialog(w http.ResponseWriter, r *http.Request) {
+	s := strings.Split(strings.TrimPrefix(r.URL.Path, "/dialogs/"), "/")
+	pi, exists := proxyInstances.GetDialog(s[0])
+	switch {
+	case len(s) != 1 && (len(s) != 3 || s[1] != "objects"):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	case !exists:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown dialog"})
+	case len(s) == 3 && r.Method == "DELETE":
+		detachObject(w, pi, s[2])
+	case len(s) == 1 && r.Method == "GET":
+		showDialog(w, pi)
+	case len(s) == 1 && r.Method == "DELETE":
+		terminateDialog(w, pi)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// listDialogs lists the active dialogs, optionally of the application given
+// by the "application" query parameter.
+func listDialogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	application := r.URL.Query().Get("application")
+	dialogs := []dialogSummary{}
+	for _, pi := range proxyInstances.Dialogs() {
+		if application != "" && application != pi.application {
+			continue
+		}
+		dialogs = append(dialogs, pi.summary())
+	}
+	writeJSON(w, http.StatusOK, dialogs)
+}
+
+// showDialog describes a dialog along with its recent events.
+func showDialog(w http.ResponseWriter, pi *proxyInstance) {
+	d := dialogDetail{dialogSummary: pi.summary(), RecentEvents: pi.recentEvents()}
+	pi.eventLock.Lock()
+	d.EventSequence = pi.eventSequence
+	pi.eventLock.Unlock()
+	d.ResponseSequence = atomic.LoadUint64(&pi.responseSequence)
+	writeJSON(w, http.StatusOK, d)
+}
+
+// terminateDialog hangs up a dialog's channels and tears it down.
+func terminateDialog(w http.ResponseWriter, pi *proxyInstance) {
+	pi.log().Info("terminating dialog on request of the admin API")
+	pi.terminate()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// detachObject removes an ARI object from a dialog without touching the
+// object in Asterisk. Its events are no longer routed to the dialog, and the
+// dialog ends when it was the last object.
+func detachObject(w http.ResponseWriter, pi *proxyInstance, object string) {
+	if !pi.owns(object) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "object is not owned by the dialog"})
+		return
+	}
+	pi.log().Info("detaching object on request of the admin API", "object", object)
+	pi.removeObject(object)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveLogLevel shows the log level, or changes it on PUT with a body such
+// as {"level": "debug"}.
+func serveLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+	case "PUT":
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		level, err := ari.ParseLevel(req.Level)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		logLevel.Set(level)
+		logger.Info("log level changed on request of the admin API", "level", level)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"level": strings.ToLower(logLevel.Level().String())})
+}
+
+// writeJSON answers a request with a JSON body.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// summary describes the proxy instance's dialog.
+func (p *proxyInstance) summary() dialogSummary {
+	return dialogSummary{
+		DialogID:    p.dialogID,
+		Application: p.application,
+		Objects:     p.objects(),
+		Started:     p.started,
+		Age:         time.Since(p.started).Seconds(),
+	}
+}
+
+// recordEvent keeps an Event among the dialog's recent events, forgetting
+// the oldest once config.Admin.RecentEvents are kept.
+func (p *proxyInstance) recordEvent(e ari.Event) {
+	max := config.Admin.RecentEvents
+	if max <= 0 {
+		max = 20
+	}
+	p.recentLock.Lock()
+	defer p.recentLock.Unlock()
+	if len(p.recent) >= max {
+		p.recent = append(p.recent[:0], p.recent[len(p.recent)-max+1:]...)
+	}
+	p.recent = append(p.recent, e)
+}
+
+// recentEvents returns a copy of the dialog's recent events, oldest first.
+func (p *proxyInstance) recentEvents() []ari.Event {
+	p.recentLock.Lock()
+	defer p.recentLock.Unlock()
+	events := make([]ari.Event, len(p.recent))
+	copy(events, p.recent)
+	return events
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dialogCounts counts the active dialogs, in total and by application, for
+// admission control.
+var dialogCounts = struct {
+	sync.Mutex
+	total int
+	apps  map[string]int
+}{apps: make(map[string]int)}
+
+// checkAdmission validates the overload action of a configuration.
+func checkAdmission(a admissionConfig) error {
+	switch a.Overload.Action {
+	case "", "hangup", "busy":
+	case "continue":
+		if a.Overload.Context == "" {
+			return fmt.Errorf("admission: the continue action needs a context")
+		}
+	default:
+		return fmt.Errorf("admission: invalid overload action %q", a.Overload.Action)
+	}
+	return nil
+}
+
+// admitDialog counts a new dialog of an application, unless that would
+// exceed the global or the application's limit on concurrent dialogs.
+// Admitted dialogs must be released when they end.
+func admitDialog(application string) bool {
+	dialogCounts.Lock()
+	defer dialogCounts.Unlock()
+	if max := config.Admission.MaxDialogs; max > 0 && dialogCounts.total >= max {
+		return false
+	}
+	if max := config.Admission.Applications[application]; max > 0 && dialogCounts.apps[application] >= max {
+		return false
+	}
+	dialogCounts.total++
+	dialogCounts.apps[application]++
+	activeDialogs.WithLabelValues(application).Inc()
+	return true
+}
+
+// releaseDialog stops counting an ended dialog of an application.
+func releaseDialog(application string) {
+	dialogCounts.Lock()
+	defer dialogCounts.Unlock()
+	dialogCounts.total--
+	dialogCounts.apps[application]--
+	activeDialogs.WithLabelValues(application).Dec()
+}
+
+// overload applies the configured overload action to a channel that was
+// refused a dialog: it continues in the dialplan, hangs up with a cause, or
+// plays a busy message and then hangs up.
+func overload(channelID string) {
+	o := config.Admission.Overload
+	channel := strings.Join([]string{"/channels/", channelID}, "")
+	var method, path string
+	switch o.Action {
+	case "continue":
+		q := url.Values{}
+		q.Set("context", o.Context)
+		if o.Extension != "" {
+			q.Set("extension", o.Extension)
+		}
+		if o.Priority > 0 {
+			q.Set("priority", strconv.Itoa(o.Priority))
+		}
+		method, path = "POST", strings.Join([]string{channel, "/continue?", q.Encode()}, "")
+	case "busy":
+		media := o.Media
+		if media == "" {
+			media = "tone:busy"
+		}
+		res, err := ariRequest("POST", strings.Join([]string{channel, "/play?media=", url.QueryEscape(media)}, ""), "")
+		if err != nil {
+			logger.Error("unable to play busy media", ari.LogChannelID, channelID, ari.LogError, err)
+		} else {
+			res.Body.Close()
+		}
+		seconds := o.BusySeconds
+		if seconds <= 0 {
+			seconds = 5
+		}
+		time.Sleep(time.Duration(seconds) * time.Second)
+		fallthrough
+	default:
+		cause := o.Cause
+		if cause <= 0 {
+			cause = 34 // no circuit/channel available
+		}
+		method, path = "DELETE", strings.Join([]string{channel, "?reason_code=", strconv.Itoa(cause)}, "")
+	}
+	res, err := ariRequest(method, path, "")
+	if err != nil {
+		logger.Error("unable to handle overload", ari.LogChannelID, channelID, "action", o.Action, ari.LogError, err)
+		return
+	}
+	res.Body.Close()
+}
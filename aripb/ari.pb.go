@@ -0,0 +1,861 @@
+// Protocol buffer definitions for the gRPC API of the go-ari-proxy. The
+// messages mirror the AppStart, Event, Command and CommandResponse envelopes
+// of the go-ari-library that travel over the message bus.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: ari.proto
+
+package aripb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Event is an ARI event from the websocket, wrapped with proxy information.
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	AriBody       string                 `protobuf:"bytes,4,opt,name=ari_body,json=ariBody,proto3" json:"ari_body,omitempty"` // the ARI event as received, in JSON
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_ari_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetAriBody() string {
+	if x != nil {
+		return x.AriBody
+	}
+	return ""
+}
+
+// AppStart announces a new dialog of an application.
+type AppStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Application   string                 `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	DialogId      string                 `protobuf:"bytes,2,opt,name=dialog_id,json=dialogId,proto3" json:"dialog_id,omitempty"`
+	ServerId      string                 `protobuf:"bytes,3,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppStart) Reset() {
+	*x = AppStart{}
+	mi := &file_ari_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppStart) ProtoMessage() {}
+
+func (x *AppStart) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppStart.ProtoReflect.Descriptor instead.
+func (*AppStart) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AppStart) GetApplication() string {
+	if x != nil {
+		return x.Application
+	}
+	return ""
+}
+
+func (x *AppStart) GetDialogId() string {
+	if x != nil {
+		return x.DialogId
+	}
+	return ""
+}
+
+func (x *AppStart) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+// Command is an ARI REST request issued by an application.
+type Command struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=unique_id,json=uniqueId,proto3" json:"unique_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	mi := &file_ari_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Command) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *Command) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Command) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Command) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+// CommandResponse is the result of a Command.
+type CommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=unique_id,json=uniqueId,proto3" json:"unique_id,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ResponseBody  string                 `protobuf:"bytes,3,opt,name=response_body,json=responseBody,proto3" json:"response_body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	mi := &file_ari_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CommandResponse) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetResponseBody() string {
+	if x != nil {
+		return x.ResponseBody
+	}
+	return ""
+}
+
+type SubscribeAppStartsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Application   string                 `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeAppStartsRequest) Reset() {
+	*x = SubscribeAppStartsRequest{}
+	mi := &file_ari_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeAppStartsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeAppStartsRequest) ProtoMessage() {}
+
+func (x *SubscribeAppStartsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeAppStartsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeAppStartsRequest) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubscribeAppStartsRequest) GetApplication() string {
+	if x != nil {
+		return x.Application
+	}
+	return ""
+}
+
+// DialogRequest is sent by the client on a Dialog stream. The first request
+// must attach to a dialog, every further request carries a Command.
+type DialogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*DialogRequest_DialogId
+	//	*DialogRequest_Command
+	Request       isDialogRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DialogRequest) Reset() {
+	*x = DialogRequest{}
+	mi := &file_ari_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DialogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DialogRequest) ProtoMessage() {}
+
+func (x *DialogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DialogRequest.ProtoReflect.Descriptor instead.
+func (*DialogRequest) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DialogRequest) GetRequest() isDialogRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *DialogRequest) GetDialogId() string {
+	if x != nil {
+		if x, ok := x.Request.(*DialogRequest_DialogId); ok {
+			return x.DialogId
+		}
+	}
+	return ""
+}
+
+func (x *DialogRequest) GetCommand() *Command {
+	if x != nil {
+		if x, ok := x.Request.(*DialogRequest_Command); ok {
+			return x.Command
+		}
+	}
+	return nil
+}
+
+type isDialogRequest_Request interface {
+	isDialogRequest_Request()
+}
+
+type DialogRequest_DialogId struct {
+	DialogId string `protobuf:"bytes,1,opt,name=dialog_id,json=dialogId,proto3,oneof"`
+}
+
+type DialogRequest_Command struct {
+	Command *Command `protobuf:"bytes,2,opt,name=command,proto3,oneof"`
+}
+
+func (*DialogRequest_DialogId) isDialogRequest_Request() {}
+
+func (*DialogRequest_Command) isDialogRequest_Request() {}
+
+// DialogResponse is sent by the proxy on a Dialog stream.
+type DialogResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Response:
+	//
+	//	*DialogResponse_Event
+	//	*DialogResponse_CommandResponse
+	Response      isDialogResponse_Response `protobuf_oneof:"response"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DialogResponse) Reset() {
+	*x = DialogResponse{}
+	mi := &file_ari_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DialogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DialogResponse) ProtoMessage() {}
+
+func (x *DialogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DialogResponse.ProtoReflect.Descriptor instead.
+func (*DialogResponse) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DialogResponse) GetResponse() isDialogResponse_Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *DialogResponse) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Response.(*DialogResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *DialogResponse) GetCommandResponse() *CommandResponse {
+	if x != nil {
+		if x, ok := x.Response.(*DialogResponse_CommandResponse); ok {
+			return x.CommandResponse
+		}
+	}
+	return nil
+}
+
+type isDialogResponse_Response interface {
+	isDialogResponse_Response()
+}
+
+type DialogResponse_Event struct {
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type DialogResponse_CommandResponse struct {
+	CommandResponse *CommandResponse `protobuf:"bytes,2,opt,name=command_response,json=commandResponse,proto3,oneof"`
+}
+
+func (*DialogResponse_Event) isDialogResponse_Response() {}
+
+func (*DialogResponse_CommandResponse) isDialogResponse_Response() {}
+
+// DialogInfo describes an active dialog.
+type DialogInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DialogId      string                 `protobuf:"bytes,1,opt,name=dialog_id,json=dialogId,proto3" json:"dialog_id,omitempty"`
+	Application   string                 `protobuf:"bytes,2,opt,name=application,proto3" json:"application,omitempty"`
+	Objects       []string               `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"` // ARI objects owned by the dialog
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DialogInfo) Reset() {
+	*x = DialogInfo{}
+	mi := &file_ari_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DialogInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DialogInfo) ProtoMessage() {}
+
+func (x *DialogInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DialogInfo.ProtoReflect.Descriptor instead.
+func (*DialogInfo) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DialogInfo) GetDialogId() string {
+	if x != nil {
+		return x.DialogId
+	}
+	return ""
+}
+
+func (x *DialogInfo) GetApplication() string {
+	if x != nil {
+		return x.Application
+	}
+	return ""
+}
+
+func (x *DialogInfo) GetObjects() []string {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+type ListDialogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Application   string                 `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"` // only list dialogs of this application if set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDialogsRequest) Reset() {
+	*x = ListDialogsRequest{}
+	mi := &file_ari_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDialogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDialogsRequest) ProtoMessage() {}
+
+func (x *ListDialogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDialogsRequest.ProtoReflect.Descriptor instead.
+func (*ListDialogsRequest) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDialogsRequest) GetApplication() string {
+	if x != nil {
+		return x.Application
+	}
+	return ""
+}
+
+type ListDialogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dialogs       []*DialogInfo          `protobuf:"bytes,1,rep,name=dialogs,proto3" json:"dialogs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDialogsResponse) Reset() {
+	*x = ListDialogsResponse{}
+	mi := &file_ari_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDialogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDialogsResponse) ProtoMessage() {}
+
+func (x *ListDialogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDialogsResponse.ProtoReflect.Descriptor instead.
+func (*ListDialogsResponse) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDialogsResponse) GetDialogs() []*DialogInfo {
+	if x != nil {
+		return x.Dialogs
+	}
+	return nil
+}
+
+type TerminateDialogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DialogId      string                 `protobuf:"bytes,1,opt,name=dialog_id,json=dialogId,proto3" json:"dialog_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminateDialogRequest) Reset() {
+	*x = TerminateDialogRequest{}
+	mi := &file_ari_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminateDialogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminateDialogRequest) ProtoMessage() {}
+
+func (x *TerminateDialogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminateDialogRequest.ProtoReflect.Descriptor instead.
+func (*TerminateDialogRequest) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TerminateDialogRequest) GetDialogId() string {
+	if x != nil {
+		return x.DialogId
+	}
+	return ""
+}
+
+type TerminateDialogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminateDialogResponse) Reset() {
+	*x = TerminateDialogResponse{}
+	mi := &file_ari_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminateDialogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminateDialogResponse) ProtoMessage() {}
+
+func (x *TerminateDialogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ari_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminateDialogResponse.ProtoReflect.Descriptor instead.
+func (*TerminateDialogResponse) Descriptor() ([]byte, []int) {
+	return file_ari_proto_rawDescGZIP(), []int{11}
+}
+
+var File_ari_proto protoreflect.FileDescriptor
+
+const file_ari_proto_rawDesc = "" +
+	"\n" +
+	"\tari.proto\x12\x05aripb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\x01\n" +
+	"\x05Event\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x19\n" +
+	"\bari_body\x18\x04 \x01(\tR\aariBody\"f\n" +
+	"\bAppStart\x12 \n" +
+	"\vapplication\x18\x01 \x01(\tR\vapplication\x12\x1b\n" +
+	"\tdialog_id\x18\x02 \x01(\tR\bdialogId\x12\x1b\n" +
+	"\tserver_id\x18\x03 \x01(\tR\bserverId\"d\n" +
+	"\aCommand\x12\x1b\n" +
+	"\tunique_id\x18\x01 \x01(\tR\buniqueId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\"t\n" +
+	"\x0fCommandResponse\x12\x1b\n" +
+	"\tunique_id\x18\x01 \x01(\tR\buniqueId\x12\x1f\n" +
+	"\vstatus_code\x18\x02 \x01(\x05R\n" +
+	"statusCode\x12#\n" +
+	"\rresponse_body\x18\x03 \x01(\tR\fresponseBody\"=\n" +
+	"\x19SubscribeAppStartsRequest\x12 \n" +
+	"\vapplication\x18\x01 \x01(\tR\vapplication\"e\n" +
+	"\rDialogRequest\x12\x1d\n" +
+	"\tdialog_id\x18\x01 \x01(\tH\x00R\bdialogId\x12*\n" +
+	"\acommand\x18\x02 \x01(\v2\x0e.aripb.CommandH\x00R\acommandB\t\n" +
+	"\arequest\"\x87\x01\n" +
+	"\x0eDialogResponse\x12$\n" +
+	"\x05event\x18\x01 \x01(\v2\f.aripb.EventH\x00R\x05event\x12C\n" +
+	"\x10command_response\x18\x02 \x01(\v2\x16.aripb.CommandResponseH\x00R\x0fcommandResponseB\n" +
+	"\n" +
+	"\bresponse\"e\n" +
+	"\n" +
+	"DialogInfo\x12\x1b\n" +
+	"\tdialog_id\x18\x01 \x01(\tR\bdialogId\x12 \n" +
+	"\vapplication\x18\x02 \x01(\tR\vapplication\x12\x18\n" +
+	"\aobjects\x18\x03 \x03(\tR\aobjects\"6\n" +
+	"\x12ListDialogsRequest\x12 \n" +
+	"\vapplication\x18\x01 \x01(\tR\vapplication\"B\n" +
+	"\x13ListDialogsResponse\x12+\n" +
+	"\adialogs\x18\x01 \x03(\v2\x11.aripb.DialogInfoR\adialogs\"5\n" +
+	"\x16TerminateDialogRequest\x12\x1b\n" +
+	"\tdialog_id\x18\x01 \x01(\tR\bdialogId\"\x19\n" +
+	"\x17TerminateDialogResponse2\xa8\x02\n" +
+	"\bAriProxy\x12I\n" +
+	"\x12SubscribeAppStarts\x12 .aripb.SubscribeAppStartsRequest\x1a\x0f.aripb.AppStart0\x01\x129\n" +
+	"\x06Dialog\x12\x14.aripb.DialogRequest\x1a\x15.aripb.DialogResponse(\x010\x01\x12D\n" +
+	"\vListDialogs\x12\x19.aripb.ListDialogsRequest\x1a\x1a.aripb.ListDialogsResponse\x12P\n" +
+	"\x0fTerminateDialog\x12\x1d.aripb.TerminateDialogRequest\x1a\x1e.aripb.TerminateDialogResponseB+Z)github.com/nvisibleinc/go-ari-proxy/aripbb\x06proto3"
+
+var (
+	file_ari_proto_rawDescOnce sync.Once
+	file_ari_proto_rawDescData []byte
+)
+
+func file_ari_proto_rawDescGZIP() []byte {
+	file_ari_proto_rawDescOnce.Do(func() {
+		file_ari_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ari_proto_rawDesc), len(file_ari_proto_rawDesc)))
+	})
+	return file_ari_proto_rawDescData
+}
+
+var file_ari_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_ari_proto_goTypes = []any{
+	(*Event)(nil),                     // 0: aripb.Event
+	(*AppStart)(nil),                  // 1: aripb.AppStart
+	(*Command)(nil),                   // 2: aripb.Command
+	(*CommandResponse)(nil),           // 3: aripb.CommandResponse
+	(*SubscribeAppStartsRequest)(nil), // 4: aripb.SubscribeAppStartsRequest
+	(*DialogRequest)(nil),             // 5: aripb.DialogRequest
+	(*DialogResponse)(nil),            // 6: aripb.DialogResponse
+	(*DialogInfo)(nil),                // 7: aripb.DialogInfo
+	(*ListDialogsRequest)(nil),        // 8: aripb.ListDialogsRequest
+	(*ListDialogsResponse)(nil),       // 9: aripb.ListDialogsResponse
+	(*TerminateDialogRequest)(nil),    // 10: aripb.TerminateDialogRequest
+	(*TerminateDialogResponse)(nil),   // 11: aripb.TerminateDialogResponse
+	(*timestamppb.Timestamp)(nil),     // 12: google.protobuf.Timestamp
+}
+var file_ari_proto_depIdxs = []int32{
+	12, // 0: aripb.Event.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 1: aripb.DialogRequest.command:type_name -> aripb.Command
+	0,  // 2: aripb.DialogResponse.event:type_name -> aripb.Event
+	3,  // 3: aripb.DialogResponse.command_response:type_name -> aripb.CommandResponse
+	7,  // 4: aripb.ListDialogsResponse.dialogs:type_name -> aripb.DialogInfo
+	4,  // 5: aripb.AriProxy.SubscribeAppStarts:input_type -> aripb.SubscribeAppStartsRequest
+	5,  // 6: aripb.AriProxy.Dialog:input_type -> aripb.DialogRequest
+	8,  // 7: aripb.AriProxy.ListDialogs:input_type -> aripb.ListDialogsRequest
+	10, // 8: aripb.AriProxy.TerminateDialog:input_type -> aripb.TerminateDialogRequest
+	1,  // 9: aripb.AriProxy.SubscribeAppStarts:output_type -> aripb.AppStart
+	6,  // 10: aripb.AriProxy.Dialog:output_type -> aripb.DialogResponse
+	9,  // 11: aripb.AriProxy.ListDialogs:output_type -> aripb.ListDialogsResponse
+	11, // 12: aripb.AriProxy.TerminateDialog:output_type -> aripb.TerminateDialogResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_ari_proto_init() }
+func file_ari_proto_init() {
+	if File_ari_proto != nil {
+		return
+	}
+	file_ari_proto_msgTypes[5].OneofWrappers = []any{
+		(*DialogRequest_DialogId)(nil),
+		(*DialogRequest_Command)(nil),
+	}
+	file_ari_proto_msgTypes[6].OneofWrappers = []any{
+		(*DialogResponse_Event)(nil),
+		(*DialogResponse_CommandResponse)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ari_proto_rawDesc), len(file_ari_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ari_proto_goTypes,
+		DependencyIndexes: file_ari_proto_depIdxs,
+		MessageInfos:      file_ari_proto_msgTypes,
+	}.Build()
+	File_ari_proto = out.File
+	file_ari_proto_goTypes = nil
+	file_ari_proto_depIdxs = nil
+}
@@ -0,0 +1,253 @@
+// Protocol buffer definitions for the gRPC API of the go-ari-proxy. The
+// messages mirror the AppStart, Event, Command and CommandResponse envelopes
+// of the go-ari-library that travel over the message bus.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: ari.proto
+
+package aripb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AriProxy_SubscribeAppStarts_FullMethodName = "/aripb.AriProxy/SubscribeAppStarts"
+	AriProxy_Dialog_FullMethodName             = "/aripb.AriProxy/Dialog"
+	AriProxy_ListDialogs_FullMethodName        = "/aripb.AriProxy/ListDialogs"
+	AriProxy_TerminateDialog_FullMethodName    = "/aripb.AriProxy/TerminateDialog"
+)
+
+// AriProxyClient is the client API for AriProxy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AriProxy offers the AppStarts and dialogs of the proxy to clients that
+// do not use the message bus.
+type AriProxyClient interface {
+	// SubscribeAppStarts streams every AppStart of an application.
+	SubscribeAppStarts(ctx context.Context, in *SubscribeAppStartsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AppStart], error)
+	// Dialog attaches to a dialog, streaming its events out and carrying
+	// Commands in and CommandResponses out.
+	Dialog(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DialogRequest, DialogResponse], error)
+	// ListDialogs lists the active dialogs.
+	ListDialogs(ctx context.Context, in *ListDialogsRequest, opts ...grpc.CallOption) (*ListDialogsResponse, error)
+	// TerminateDialog hangs up a dialog's channels and tears it down.
+	TerminateDialog(ctx context.Context, in *TerminateDialogRequest, opts ...grpc.CallOption) (*TerminateDialogResponse, error)
+}
+
+type ariProxyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAriProxyClient(cc grpc.ClientConnInterface) AriProxyClient {
+	return &ariProxyClient{cc}
+}
+
+func (c *ariProxyClient) SubscribeAppStarts(ctx context.Context, in *SubscribeAppStartsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AppStart], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AriProxy_ServiceDesc.Streams[0], AriProxy_SubscribeAppStarts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeAppStartsRequest, AppStart]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AriProxy_SubscribeAppStartsClient = grpc.ServerStreamingClient[AppStart]
+
+func (c *ariProxyClient) Dialog(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DialogRequest, DialogResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AriProxy_ServiceDesc.Streams[1], AriProxy_Dialog_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DialogRequest, DialogResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AriProxy_DialogClient = grpc.BidiStreamingClient[DialogRequest, DialogResponse]
+
+func (c *ariProxyClient) ListDialogs(ctx context.Context, in *ListDialogsRequest, opts ...grpc.CallOption) (*ListDialogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDialogsResponse)
+	err := c.cc.Invoke(ctx, AriProxy_ListDialogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ariProxyClient) TerminateDialog(ctx context.Context, in *TerminateDialogRequest, opts ...grpc.CallOption) (*TerminateDialogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TerminateDialogResponse)
+	err := c.cc.Invoke(ctx, AriProxy_TerminateDialog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AriProxyServer is the server API for AriProxy service.
+// All implementations must embed UnimplementedAriProxyServer
+// for forward compatibility.
+//
+// AriProxy offers the AppStarts and dialogs of the proxy to clients that
+// do not use the message bus.
+type AriProxyServer interface {
+	// SubscribeAppStarts streams every AppStart of an application.
+	SubscribeAppStarts(*SubscribeAppStartsRequest, grpc.ServerStreamingServer[AppStart]) error
+	// Dialog attaches to a dialog, streaming its events out and carrying
+	// Commands in and CommandResponses out.
+	Dialog(grpc.BidiStreamingServer[DialogRequest, DialogResponse]) error
+	// ListDialogs lists the active dialogs.
+	ListDialogs(context.Context, *ListDialogsRequest) (*ListDialogsResponse, error)
+	// TerminateDialog hangs up a dialog's channels and tears it down.
+	TerminateDialog(context.Context, *TerminateDialogRequest) (*TerminateDialogResponse, error)
+	mustEmbedUnimplementedAriProxyServer()
+}
+
+// UnimplementedAriProxyServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAriProxyServer struct{}
+
+func (UnimplementedAriProxyServer) SubscribeAppStarts(*SubscribeAppStartsRequest, grpc.ServerStreamingServer[AppStart]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeAppStarts not implemented")
+}
+func (UnimplementedAriProxyServer) Dialog(grpc.BidiStreamingServer[DialogRequest, DialogResponse]) error {
+	return status.Error(codes.Unimplemented, "method Dialog not implemented")
+}
+func (UnimplementedAriProxyServer) ListDialogs(context.Context, *ListDialogsRequest) (*ListDialogsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDialogs not implemented")
+}
+func (UnimplementedAriProxyServer) TerminateDialog(context.Context, *TerminateDialogRequest) (*TerminateDialogResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TerminateDialog not implemented")
+}
+func (UnimplementedAriProxyServer) mustEmbedUnimplementedAriProxyServer() {}
+func (UnimplementedAriProxyServer) testEmbeddedByValue()                  {}
+
+// UnsafeAriProxyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AriProxyServer will
+// result in compilation errors.
+type UnsafeAriProxyServer interface {
+	mustEmbedUnimplementedAriProxyServer()
+}
+
+func RegisterAriProxyServer(s grpc.ServiceRegistrar, srv AriProxyServer) {
+	// If the following call panics, it indicates UnimplementedAriProxyServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AriProxy_ServiceDesc, srv)
+}
+
+func _AriProxy_SubscribeAppStarts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAppStartsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AriProxyServer).SubscribeAppStarts(m, &grpc.GenericServerStream[SubscribeAppStartsRequest, AppStart]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AriProxy_SubscribeAppStartsServer = grpc.ServerStreamingServer[AppStart]
+
+func _AriProxy_Dialog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AriProxyServer).Dialog(&grpc.GenericServerStream[DialogRequest, DialogResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AriProxy_DialogServer = grpc.BidiStreamingServer[DialogRequest, DialogResponse]
+
+func _AriProxy_ListDialogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDialogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AriProxyServer).ListDialogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AriProxy_ListDialogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AriProxyServer).ListDialogs(ctx, req.(*ListDialogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AriProxy_TerminateDialog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TerminateDialogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AriProxyServer).TerminateDialog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AriProxy_TerminateDialog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AriProxyServer).TerminateDialog(ctx, req.(*TerminateDialogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AriProxy_ServiceDesc is the grpc.ServiceDesc for AriProxy service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AriProxy_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aripb.AriProxy",
+	HandlerType: (*AriProxyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDialogs",
+			Handler:    _AriProxy_ListDialogs_Handler,
+		},
+		{
+			MethodName: "TerminateDialog",
+			Handler:    _AriProxy_TerminateDialog_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeAppStarts",
+			Handler:       _AriProxy_SubscribeAppStarts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Dialog",
+			Handler:       _AriProxy_Dialog_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ari.proto",
+}
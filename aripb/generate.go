@@ -0,0 +1,5 @@
+// Package aripb holds the protocol buffer messages and gRPC service of the
+// go-ari-proxy gRPC API.
+package aripb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ari.proto
@@ -0,0 +1,510 @@
+package aritest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errorBody is the body ARI answers failed requests with.
+type errorBody struct {
+	Message string `json:"message"`
+}
+
+// fail returns the status and body of a failed request.
+func fail(status int, message string) (int, interface{}) {
+	return status, errorBody{Message: message}
+}
+
+// serveREST answers a request on the REST interface. Requests are handled
+// one at a time, so that the events they cause are sent in order.
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/ari")
+	params := parameters(r)
+	s.lock.Lock()
+	status, body := s.route(r.Method, strings.Split(strings.Trim(path, "/"), "/"), params)
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, StatusCode: status})
+	var response []byte
+	if body != nil {
+		// marshal under the lock, as the body may share the fake's state
+		response, _ = json.Marshal(body)
+	}
+	s.lock.Unlock()
+
+	if response != nil {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	w.Write(response)
+}
+
+// parameters merges the parameters of the query string and of the JSON
+// body of a request, which ARI both accepts.
+func parameters(r *http.Request) url.Values {
+	params := r.URL.Query()
+	var body map[string]interface{}
+	if json.NewDecoder(r.Body).Decode(&body) == nil {
+		for key, value := range body {
+			if v, ok := value.(string); ok {
+				params.Set(key, v)
+			} else {
+				params.Set(key, fmt.Sprint(value))
+			}
+		}
+	}
+	return params
+}
+
+// route dispatches a request by its resource. The lock must be held.
+func (s *Server) route(method string, path []string, params url.Values) (int, interface{}) {
+	switch path[0] {
+	case "channels":
+		return s.routeChannels(method, path[1:], params)
+	case "bridges":
+		return s.routeBridges(method, path[1:], params)
+	case "playbacks":
+		return s.routePlaybacks(method, path[1:], params)
+	case "recordings":
+		return s.routeRecordings(method, path[1:], params)
+	}
+	return fail(http.StatusNotFound, "Resource not found")
+}
+
+// routeChannels handles the requests on /channels.
+func (s *Server) routeChannels(method string, path []string, params url.Values) (int, interface{}) {
+	if len(path) == 0 {
+		switch method {
+		case "GET":
+			channels := []ari.Channel{}
+			for _, c := range s.channels {
+				channels = append(channels, c.Channel)
+			}
+			return http.StatusOK, channels
+		case "POST":
+			return s.originate(params.Get("channelId"), params)
+		}
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+	if len(path) == 1 && method == "POST" {
+		return s.originate(path[0], params)
+	}
+	c, ok := s.channels[path[0]]
+	if !ok {
+		return fail(http.StatusNotFound, "Channel not found")
+	}
+	if len(path) == 1 {
+		switch method {
+		case "GET":
+			return http.StatusOK, c.Channel
+		case "DELETE":
+			s.hangup(c)
+			return http.StatusNoContent, nil
+		}
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	switch operation := path[1]; {
+	case operation == "answer" && method == "POST":
+		s.setState(c, "Up")
+		return http.StatusNoContent, nil
+	case operation == "continue" && method == "POST":
+		// the channel leaves Stasis for the dialplan, where the fake loses it
+		if b, ok := s.bridges[c.bridge]; ok {
+			s.leaveBridge(b, c)
+		}
+		delete(s.channels, c.Id)
+		s.emit(c.application, "StasisEnd", map[string]interface{}{"channel": c.Channel})
+		return http.StatusNoContent, nil
+	case operation == "ring" || operation == "mute" || operation == "hold" || operation == "moh" || operation == "silence":
+		if method == "POST" && operation == "ring" {
+			s.setState(c, "Ringing")
+		}
+		return http.StatusNoContent, nil
+	case operation == "dtmf" && method == "POST":
+		if params.Get("dtmf") == "" {
+			return fail(http.StatusBadRequest, "DTMF is required")
+		}
+		return http.StatusNoContent, nil
+	case operation == "play" && method == "POST":
+		id := params.Get("playbackId")
+		if len(path) == 3 {
+			id = path[2]
+		}
+		return s.play("channel:"+c.Id, c.application, id, params)
+	case operation == "record" && method == "POST":
+		return s.record("channel:"+c.Id, c.application, params)
+	}
+	return fail(http.StatusNotFound, "Resource not found")
+}
+
+// originate creates an outgoing channel, which enters the Stasis application
+// named by the app parameter once the far end answers. Channels originated
+// to the dialplan are not tracked.
+func (s *Server) originate(id string, params url.Values) (int, interface{}) {
+	endpoint := params.Get("endpoint")
+	if endpoint == "" {
+		return fail(http.StatusBadRequest, "Endpoint is required")
+	}
+	if _, ok := s.channels[id]; ok && id != "" {
+		return fail(http.StatusConflict, "Channel with given unique ID already exists")
+	}
+	app := params.Get("app")
+	c := s.newChannel(app, endpoint, "Down")
+	if id != "" {
+		delete(s.channels, c.Id)
+		c.Id = id
+		s.channels[id] = c
+	}
+	if app == "" {
+		delete(s.channels, c.Id)
+		return http.StatusOK, c.Channel
+	}
+	args := []string{}
+	if params.Get("appArgs") != "" {
+		args = strings.Split(params.Get("appArgs"), ",")
+	}
+	response := c.Channel
+	s.later(func() {
+		if s.channels[c.Id] != c {
+			return
+		}
+		c.State = "Up"
+		s.emit(app, "StasisStart", map[string]interface{}{"channel": c.Channel, "args": args})
+	})
+	return http.StatusOK, response
+}
+
+// setState changes the state of a channel, announcing the change.
+func (s *Server) setState(c *channel, state string) {
+	if c.State == state {
+		return
+	}
+	c.State = state
+	s.emit(c.application, "ChannelStateChange", map[string]interface{}{"channel": c.Channel})
+}
+
+// routeBridges handles the requests on /bridges.
+func (s *Server) routeBridges(method string, path []string, params url.Values) (int, interface{}) {
+	if len(path) == 0 {
+		switch method {
+		case "GET":
+			bridges := []ari.Bridge{}
+			for _, b := range s.bridges {
+				bridges = append(bridges, b.Bridge)
+			}
+			return http.StatusOK, bridges
+		case "POST":
+			return s.createBridge(params.Get("bridgeId"), params)
+		}
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+	if len(path) == 1 && method == "POST" {
+		return s.createBridge(path[0], params)
+	}
+	b, ok := s.bridges[path[0]]
+	if !ok {
+		return fail(http.StatusNotFound, "Bridge not found")
+	}
+	if len(path) == 1 {
+		switch method {
+		case "GET":
+			return http.StatusOK, b.Bridge
+		case "DELETE":
+			for _, id := range b.Channels {
+				if c, ok := s.channels[id]; ok {
+					s.leaveBridge(b, c)
+				}
+			}
+			delete(s.bridges, b.Id)
+			s.emit(b.application, "BridgeDestroyed", map[string]interface{}{"bridge": b.Bridge})
+			return http.StatusNoContent, nil
+		}
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	switch operation := path[1]; {
+	case operation == "addChannel" && method == "POST":
+		var channels []*channel
+		for _, id := range strings.Split(params.Get("channel"), ",") {
+			c, ok := s.channels[id]
+			if !ok {
+				return fail(http.StatusBadRequest, "Channel not found")
+			}
+			channels = append(channels, c)
+		}
+		for _, c := range channels {
+			if c.bridge == b.Id {
+				continue
+			}
+			if other, ok := s.bridges[c.bridge]; ok {
+				s.leaveBridge(other, c)
+			}
+			// the application of the first channel follows the bridge
+			if b.application == "" {
+				b.application = c.application
+			}
+			c.bridge = b.Id
+			b.Channels = append(b.Channels, c.Id)
+			s.emit(c.application, "ChannelEnteredBridge", map[string]interface{}{"bridge": b.Bridge, "channel": c.Channel})
+		}
+		return http.StatusNoContent, nil
+	case operation == "removeChannel" && method == "POST":
+		var channels []*channel
+		for _, id := range strings.Split(params.Get("channel"), ",") {
+			c, ok := s.channels[id]
+			if !ok {
+				return fail(http.StatusBadRequest, "Channel not found")
+			}
+			if c.bridge != b.Id {
+				return fail(http.StatusUnprocessableEntity, "Channel not in this bridge")
+			}
+			channels = append(channels, c)
+		}
+		for _, c := range channels {
+			s.leaveBridge(b, c)
+		}
+		return http.StatusNoContent, nil
+	case operation == "moh":
+		return http.StatusNoContent, nil
+	case operation == "play" && method == "POST":
+		id := params.Get("playbackId")
+		if len(path) == 3 {
+			id = path[2]
+		}
+		return s.play("bridge:"+b.Id, b.application, id, params)
+	case operation == "record" && method == "POST":
+		return s.record("bridge:"+b.Id, b.application, params)
+	}
+	return fail(http.StatusNotFound, "Resource not found")
+}
+
+// createBridge creates a bridge, or updates the name of an existing one.
+func (s *Server) createBridge(id string, params url.Values) (int, interface{}) {
+	if b, ok := s.bridges[id]; ok {
+		if name := params.Get("name"); name != "" {
+			b.Name = name
+		}
+		return http.StatusOK, b.Bridge
+	}
+	if id == "" {
+		id = ari.UUID()
+	}
+	bridgeType := params.Get("type")
+	if bridgeType == "" {
+		bridgeType = "mixing"
+	}
+	b := &bridge{Bridge: ari.Bridge{
+		Id:           id,
+		Technology:   "simple_bridge",
+		Bridge_Type:  bridgeType,
+		Bridge_Class: "stasis",
+		Creator:      "Stasis",
+		Name:         params.Get("name"),
+		Channels:     []string{},
+	}}
+	s.bridges[id] = b
+	return http.StatusOK, b.Bridge
+}
+
+// play queues a playback of a media URI on a channel or bridge, which starts
+// after the start delay.
+func (s *Server) play(target string, app string, id string, params url.Values) (int, interface{}) {
+	if params.Get("media") == "" {
+		return fail(http.StatusBadRequest, "Media is required")
+	}
+	if id == "" {
+		id = ari.UUID()
+	}
+	if _, ok := s.playbacks[id]; ok {
+		return fail(http.StatusConflict, "Playback with given ID already exists")
+	}
+	language := params.Get("lang")
+	if language == "" {
+		language = "en"
+	}
+	p := &playback{application: app, Playback: ari.Playback{
+		Id:         id,
+		Media_Uri:  params.Get("media"),
+		Target_Uri: target,
+		Language:   language,
+		State:      "queued",
+	}}
+	s.playbacks[id] = p
+	response := p.Playback
+	s.later(func() {
+		if s.playbacks[id] != p || p.State != "queued" {
+			return
+		}
+		p.State = "playing"
+		s.emit(app, "PlaybackStarted", map[string]interface{}{"playback": p.Playback})
+		s.resume(p)
+	})
+	return http.StatusCreated, response
+}
+
+// resume lets a playback play for the playback duration.
+func (s *Server) resume(p *playback) {
+	p.timer = time.AfterFunc(s.PlaybackDuration, func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if s.playbacks[p.Id] == p && p.State == "playing" {
+			s.finishPlayback(p)
+		}
+	})
+}
+
+// routePlaybacks handles the requests on /playbacks.
+func (s *Server) routePlaybacks(method string, path []string, params url.Values) (int, interface{}) {
+	if len(path) == 0 {
+		return fail(http.StatusNotFound, "Resource not found")
+	}
+	p, ok := s.playbacks[path[0]]
+	if !ok {
+		return fail(http.StatusNotFound, "The playback cannot be found")
+	}
+	switch {
+	case len(path) == 1 && method == "GET":
+		return http.StatusOK, p.Playback
+	case len(path) == 1 && method == "DELETE":
+		s.finishPlayback(p)
+		return http.StatusNoContent, nil
+	case len(path) == 2 && path[1] == "control" && method == "POST":
+		operation := params.Get("operation")
+		switch operation {
+		case "pause", "unpause", "restart", "reverse", "forward":
+		default:
+			return fail(http.StatusBadRequest, "The provided operation parameter was invalid")
+		}
+		if p.State == "queued" {
+			return fail(http.StatusConflict, "The operation cannot be performed in the playback's current state")
+		}
+		switch {
+		case operation == "pause" && p.State == "playing":
+			p.timer.Stop()
+			p.State = "paused"
+		case operation == "unpause" && p.State == "paused", operation == "restart":
+			p.timer.Stop()
+			p.State = "playing"
+			s.resume(p)
+		}
+		return http.StatusNoContent, nil
+	}
+	return fail(http.StatusNotFound, "Resource not found")
+}
+
+// record queues a live recording of a channel or bridge, which starts after
+// the start delay. As in ARI, a recording cannot replace a stored one unless
+// ifExists is overwrite.
+func (s *Server) record(target string, app string, params url.Values) (int, interface{}) {
+	name, format := params.Get("name"), params.Get("format")
+	if name == "" || format == "" {
+		return fail(http.StatusBadRequest, "Name and format are required")
+	}
+	if _, ok := s.recordings[name]; ok {
+		return fail(http.StatusConflict, "A recording with the same name is in progress")
+	}
+	if _, ok := s.stored[name]; ok && params.Get("ifExists") != "overwrite" {
+		return fail(http.StatusConflict, "A recording with the same name already exists")
+	}
+	r := &recording{application: app, LiveRecording: ari.LiveRecording{
+		Name:       name,
+		Format:     format,
+		Target_Uri: target,
+		State:      "queued",
+	}}
+	s.recordings[name] = r
+	response := r.LiveRecording
+	maxDuration, _ := strconv.Atoi(params.Get("maxDurationSeconds"))
+	s.later(func() {
+		if s.recordings[name] != r || r.State != "queued" {
+			return
+		}
+		r.State = "recording"
+		s.emit(app, "RecordingStarted", map[string]interface{}{"recording": r.LiveRecording})
+		if maxDuration > 0 {
+			r.timer = time.AfterFunc(time.Duration(maxDuration)*time.Second, func() {
+				s.lock.Lock()
+				defer s.lock.Unlock()
+				if s.recordings[name] == r {
+					s.finishRecording(r, "done")
+				}
+			})
+		}
+	})
+	return http.StatusCreated, response
+}
+
+// routeRecordings handles the requests on /recordings.
+func (s *Server) routeRecordings(method string, path []string, params url.Values) (int, interface{}) {
+	if len(path) >= 1 && path[0] == "stored" {
+		if len(path) == 1 && method == "GET" {
+			stored := []ari.StoredRecording{}
+			for _, r := range s.stored {
+				stored = append(stored, r)
+			}
+			return http.StatusOK, stored
+		}
+		if len(path) != 2 {
+			return fail(http.StatusNotFound, "Resource not found")
+		}
+		r, ok := s.stored[path[1]]
+		if !ok {
+			return fail(http.StatusNotFound, "Recording not found")
+		}
+		switch method {
+		case "GET":
+			return http.StatusOK, r
+		case "DELETE":
+			delete(s.stored, r.Name)
+			return http.StatusNoContent, nil
+		}
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+	if len(path) < 2 || path[0] != "live" {
+		return fail(http.StatusNotFound, "Resource not found")
+	}
+	r, ok := s.recordings[path[1]]
+	if !ok {
+		return fail(http.StatusNotFound, "Recording not found")
+	}
+	if len(path) == 2 {
+		switch method {
+		case "GET":
+			return http.StatusOK, r.LiveRecording
+		case "DELETE":
+			s.finishRecording(r, "canceled")
+			return http.StatusNoContent, nil
+		}
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	switch operation := path[2]; {
+	case operation == "stop" && method == "POST":
+		s.finishRecording(r, "done")
+		return http.StatusNoContent, nil
+	case operation == "pause":
+		if r.State == "queued" {
+			return fail(http.StatusConflict, "Recording not in session")
+		}
+		if method == "POST" {
+			r.State = "paused"
+		} else {
+			r.State = "recording"
+		}
+		return http.StatusNoContent, nil
+	case operation == "unpause" && method == "POST":
+		if r.State == "queued" {
+			return fail(http.StatusConflict, "Recording not in session")
+		}
+		r.State = "recording"
+		return http.StatusNoContent, nil
+	case operation == "mute" || operation == "unmute":
+		return http.StatusNoContent, nil
+	}
+	return fail(http.StatusNotFound, "Resource not found")
+}
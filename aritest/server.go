@@ -0,0 +1,423 @@
+// Package aritest provides a fake Asterisk REST Interface for testing the
+// proxy and its applications without an Asterisk server.
+//
+// The fake serves the events websocket and the REST resources of channels,
+// bridges, playbacks and recordings. Resources go through the states a real
+// Asterisk puts them in, and every change is announced with the event
+// Asterisk would send to the Stasis application owning the resource. The
+// test plays the part of the callers: Call places a channel in Stasis, Hangup
+// and DTMF act on it from the far end.
+package aritest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"golang.org/x/net/websocket"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request is a REST request the fake has answered.
+type Request struct {
+	Method     string
+	Path       string // path relative to the ARI root, such as /channels/1.1/answer
+	StatusCode int
+}
+
+// Server is a fake ARI server listening on the loopback interface.
+type Server struct {
+	URL          string // root of the REST interface, for the stasis_url of the proxy
+	WebsocketURL string // events websocket, for the websocket_url of the proxy
+
+	// APIKey, when set, is the user:password the requests must carry in
+	// their api_key parameter.
+	APIKey string
+	// StartDelay is how long new resources take to start: originated
+	// channels to be answered and enter Stasis, and queued playbacks and
+	// recordings to start. It gives the proxy the time to learn their IDs
+	// from the responses, 20 ms by default.
+	StartDelay time.Duration
+	// PlaybackDuration is how long a playback plays before it finishes,
+	// 100 ms by default.
+	PlaybackDuration time.Duration
+
+	server     *httptest.Server
+	lock       sync.Mutex
+	sequence   int
+	epoch      int64
+	sockets    map[string][]*websocket.Conn // websockets by application
+	connected  chan bool                    // signalled when a websocket connects
+	channels   map[string]*channel
+	bridges    map[string]*bridge
+	playbacks  map[string]*playback
+	recordings map[string]*recording
+	stored     map[string]ari.StoredRecording
+	requests   []Request
+}
+
+// channel is a channel of the fake, in Stasis until it leaves for the
+// dialplan or hangs up.
+type channel struct {
+	ari.Channel
+	application string
+	bridge      string // bridge the channel is in, if any
+}
+
+// bridge is a bridge of the fake and the channels in it.
+type bridge struct {
+	ari.Bridge
+	application string
+}
+
+// playback is a playback of the fake, which finishes on its own once it has
+// played for the playback duration, unless it is stopped first.
+type playback struct {
+	ari.Playback
+	application string
+	timer       *time.Timer // finishes the playback, nil until it starts
+}
+
+// recording is a live recording of the fake, which runs until it is stopped,
+// cancelled or reaches its maximum duration.
+type recording struct {
+	ari.LiveRecording
+	application string
+	timer       *time.Timer // stops the recording at its maximum duration, if any
+}
+
+// NewServer starts a fake ARI server on a random port. The caller should
+// Close it when finished.
+func NewServer() *Server {
+	s := newServer()
+	s.start(httptest.NewUnstartedServer(s.handler()))
+	return s
+}
+
+// NewServerAt starts a fake ARI server listening on addr, such as
+// 127.0.0.1:8088, for a proxy running in another process to connect to. The
+// caller should Close it when finished.
+func NewServerAt(addr string) (*Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := newServer()
+	server := httptest.NewUnstartedServer(s.handler())
+	server.Listener.Close()
+	server.Listener = l
+	s.start(server)
+	return s, nil
+}
+
+// newServer creates a fake ARI server with no resources.
+func newServer() *Server {
+	return &Server{
+		StartDelay:       20 * time.Millisecond,
+		PlaybackDuration: 100 * time.Millisecond,
+		epoch:            time.Now().Unix(),
+		sockets:          make(map[string][]*websocket.Conn),
+		connected:        make(chan bool, 1),
+		channels:         make(map[string]*channel),
+		bridges:          make(map[string]*bridge),
+		playbacks:        make(map[string]*playback),
+		recordings:       make(map[string]*recording),
+		stored:           make(map[string]ari.StoredRecording),
+	}
+}
+
+// handler routes the events websocket and the REST interface under /ari, as
+// Asterisk does.
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/ari/events", s.authenticate(websocket.Server{Handler: s.serveEvents}))
+	mux.Handle("/ari/", s.authenticate(http.HandlerFunc(s.serveREST)))
+	return mux
+}
+
+// start starts serving and sets the URLs of the server.
+func (s *Server) start(server *httptest.Server) {
+	server.Start()
+	s.server = server
+	s.URL = server.URL + "/ari"
+	s.WebsocketURL = strings.Replace(s.URL, "http://", "ws://", 1) + "/events"
+}
+
+// Close disconnects the websockets and shuts the server down.
+func (s *Server) Close() {
+	s.Disconnect()
+	s.server.CloseClientConnections()
+	s.server.Close()
+}
+
+// authenticate refuses the requests without the API key, when one is set.
+func (s *Server) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.APIKey != "" && r.URL.Query().Get("api_key") != s.APIKey {
+			http.Error(w, `{"message": "Authentication required"}`, http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// serveEvents subscribes a websocket to the events of the application named
+// by its app parameter, until it is closed.
+func (s *Server) serveEvents(ws *websocket.Conn) {
+	app := ws.Request().URL.Query().Get("app")
+	if app == "" {
+		return
+	}
+	s.lock.Lock()
+	s.sockets[app] = append(s.sockets[app], ws)
+	s.lock.Unlock()
+	select {
+	case s.connected <- true:
+	default:
+	}
+
+	// wait for the client to go away, discarding whatever it sends
+	var discard string
+	for websocket.Message.Receive(ws, &discard) == nil {
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sockets := s.sockets[app]
+	for i := range sockets {
+		if sockets[i] == ws {
+			s.sockets[app] = append(sockets[:i], sockets[i+1:]...)
+			break
+		}
+	}
+}
+
+// Connected reports how many websockets are subscribed to the events of an
+// application.
+func (s *Server) Connected(app string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.sockets[app])
+}
+
+// WaitConnected waits until a websocket is subscribed to the events of an
+// application, or returns an error after the timeout.
+func (s *Server) WaitConnected(app string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for s.Connected(app) == 0 {
+		select {
+		case <-s.connected:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			return fmt.Errorf("aritest: no websocket connected for %s", app)
+		}
+	}
+	return nil
+}
+
+// Disconnect closes every websocket, as when Asterisk restarts.
+func (s *Server) Disconnect() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for app, sockets := range s.sockets {
+		for _, ws := range sockets {
+			ws.Close()
+		}
+		delete(s.sockets, app)
+	}
+}
+
+// Requests returns the REST requests the fake has answered, in order.
+func (s *Server) Requests() []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// Channel returns a channel in Stasis.
+func (s *Server) Channel(id string) (ari.Channel, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c, ok := s.channels[id]
+	if !ok {
+		return ari.Channel{}, false
+	}
+	return c.Channel, true
+}
+
+// Bridge returns a bridge.
+func (s *Server) Bridge(id string) (ari.Bridge, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	b, ok := s.bridges[id]
+	if !ok {
+		return ari.Bridge{}, false
+	}
+	br := b.Bridge
+	br.Channels = append([]string{}, b.Channels...)
+	return br, true
+}
+
+// Playback returns a playback that has not finished.
+func (s *Server) Playback(id string) (ari.Playback, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p, ok := s.playbacks[id]
+	if !ok {
+		return ari.Playback{}, false
+	}
+	return p.Playback, true
+}
+
+// Recording returns a live recording, or the stored recording once it has
+// finished.
+func (s *Server) Recording(name string) (ari.LiveRecording, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if r, ok := s.recordings[name]; ok {
+		return r.LiveRecording, true
+	}
+	if _, ok := s.stored[name]; ok {
+		return ari.LiveRecording{Name: name, State: "done"}, true
+	}
+	return ari.LiveRecording{}, false
+}
+
+// Call places an incoming channel in the Stasis application, as the Stasis
+// dialplan application does, and returns its ID.
+func (s *Server) Call(app string, args ...string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c := s.newChannel(app, "PJSIP/caller", "Ring")
+	if args == nil {
+		args = []string{}
+	}
+	s.emit(app, "StasisStart", map[string]interface{}{"channel": c.Channel, "args": args})
+	return c.Id
+}
+
+// Hangup hangs a channel up from the far end. It returns false if the
+// channel is not in Stasis.
+func (s *Server) Hangup(id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c, ok := s.channels[id]
+	if !ok {
+		return false
+	}
+	s.emit(c.application, "ChannelHangupRequest", map[string]interface{}{"channel": c.Channel, "cause": 16})
+	s.hangup(c)
+	return true
+}
+
+// DTMF presses a key on the far end of a channel. It returns false if the
+// channel is not in Stasis.
+func (s *Server) DTMF(id string, digit string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c, ok := s.channels[id]
+	if !ok {
+		return false
+	}
+	s.emit(c.application, "ChannelDtmfReceived", map[string]interface{}{"channel": c.Channel, "digit": digit, "duration_ms": 100})
+	return true
+}
+
+// newChannel creates a channel in Stasis. The lock must be held.
+func (s *Server) newChannel(app string, name string, state string) *channel {
+	s.sequence++
+	c := &channel{application: app}
+	c.Id = fmt.Sprintf("%d.%d", s.epoch, s.sequence)
+	c.Name = fmt.Sprintf("%s-%08x", name, s.sequence)
+	c.State = state
+	c.Caller = ari.CallerID{Name: "Caller", Number: "1000"}
+	c.Dialplan = ari.DialplanCEP{Context: "default", Exten: "1000", Priority: 1}
+	c.Creationtime = time.Now().Format("2006-01-02T15:04:05.000-0700")
+	s.channels[c.Id] = c
+	return c
+}
+
+// hangup removes a channel from its bridge and from Stasis and destroys it.
+// The lock must be held.
+func (s *Server) hangup(c *channel) {
+	if b, ok := s.bridges[c.bridge]; ok {
+		s.leaveBridge(b, c)
+	}
+	for _, p := range s.playbacks {
+		if p.Target_Uri == "channel:"+c.Id {
+			s.finishPlayback(p)
+		}
+	}
+	for _, r := range s.recordings {
+		if r.Target_Uri == "channel:"+c.Id {
+			s.finishRecording(r, "done")
+		}
+	}
+	delete(s.channels, c.Id)
+	s.emit(c.application, "StasisEnd", map[string]interface{}{"channel": c.Channel})
+	s.emit(c.application, "ChannelDestroyed", map[string]interface{}{"channel": c.Channel, "cause": 16, "cause_txt": "Normal Clearing"})
+}
+
+// leaveBridge removes a channel from a bridge. The lock must be held.
+func (s *Server) leaveBridge(b *bridge, c *channel) {
+	for i, id := range b.Channels {
+		if id == c.Id {
+			b.Channels = append(b.Channels[:i:i], b.Channels[i+1:]...)
+			break
+		}
+	}
+	c.bridge = ""
+	s.emit(c.application, "ChannelLeftBridge", map[string]interface{}{"bridge": b.Bridge, "channel": c.Channel})
+}
+
+// finishPlayback ends a playback. The lock must be held.
+func (s *Server) finishPlayback(p *playback) {
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	delete(s.playbacks, p.Id)
+	p.State = "done"
+	s.emit(p.application, "PlaybackFinished", map[string]interface{}{"playback": p.Playback})
+}
+
+// finishRecording ends a live recording in the given state, storing it
+// unless it was cancelled. The lock must be held.
+func (s *Server) finishRecording(r *recording, state string) {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	delete(s.recordings, r.Name)
+	r.State = state
+	if state == "done" {
+		s.stored[r.Name] = ari.StoredRecording{Name: r.Name, Format: r.Format}
+	}
+	s.emit(r.application, "RecordingFinished", map[string]interface{}{"recording": r.LiveRecording})
+}
+
+// later runs f with the lock held once the start delay has passed.
+func (s *Server) later(f func()) {
+	time.AfterFunc(s.StartDelay, func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		f()
+	})
+}
+
+// emit sends an event to the websockets of an application. The lock must be
+// held, so that events reach the websockets in the order they happened.
+func (s *Server) emit(app string, eventType string, fields map[string]interface{}) {
+	fields["type"] = eventType
+	fields["application"] = app
+	fields["timestamp"] = time.Now().Format("2006-01-02T15:04:05.000-0700")
+	message, err := json.Marshal(fields)
+	if err != nil {
+		panic(err)
+	}
+	for _, ws := range s.sockets[app] {
+		ws.SetWriteDeadline(time.Now().Add(time.Second))
+		websocket.Message.Send(ws, string(message))
+	}
+}
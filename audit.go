@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// audit writes the audit trail of the dialogs, or is nil when auditing is
+// disabled.
+var audit *auditLog
+
+// auditRecord is an entry of the audit trail of a dialog. Type is one of
+// "app_start", "event", "command" or "teardown", and the fields of that type
+// are set.
+type auditRecord struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	DialogID    string    `json:"dialog_id"`
+	Application string    `json:"application"`
+	ChannelID   string    `json:"channel_id,omitempty"`
+	EventType   string    `json:"event_type,omitempty"`
+	UniqueID    string    `json:"unique_id,omitempty"`
+	Method      string    `json:"method,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Latency     float64   `json:"latency_ms,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// callDetailRecord summarizes a dialog once it has ended.
+type callDetailRecord struct {
+	Type           string    `json:"type"` // always "cdr"
+	DialogID       string    `json:"dialog_id"`
+	Application    string    `json:"application"`
+	ServerID       string    `json:"server_id,omitempty"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Duration       float64   `json:"duration_seconds"`
+	Reason         string    `json:"reason"`
+	Channels       []string  `json:"channels"`
+	Events         int       `json:"events"`
+	Commands       int       `json:"commands"`
+	CommandsFailed int       `json:"commands_failed"`
+}
+
+// auditLog writes audit records, one JSON object per line, in the
+// background so that dialogs never wait for the audit trail.
+type auditLog struct {
+	records chan []byte
+	sink    io.Writer
+}
+
+// initAudit opens the sink of the audit trail from the audit configuration.
+func initAudit() error {
+	c := config.Audit
+	var sink io.Writer
+	switch c.Output {
+	case "":
+		return nil
+	case "file":
+		if c.File == "" {
+			return fmt.Errorf("audit: the file output needs a file")
+		}
+		f, err := openRotatingFile(c.File, int64(c.MaxSizeMB)*1024*1024, c.MaxBackups)
+		if err != nil {
+			return fmt.Errorf("audit: %s", err)
+		}
+		sink = f
+	case "bus":
+		topic := c.Topic
+		if topic == "" {
+			topic = "audit"
+		}
+		sink = busWriter(ari.InitProducer(topic))
+	default:
+		return fmt.Errorf("audit: invalid output %q", c.Output)
+	}
+	audit = &auditLog{records: make(chan []byte, 4096), sink: sink}
+	go audit.run()
+	return nil
+}
+
+// run writes the queued records to the sink.
+func (a *auditLog) run() {
+	for r := range a.records {
+		if _, err := a.sink.Write(r); err != nil {
+			logger.Error("unable to write audit record", ari.LogError, err)
+		}
+	}
+}
+
+// record queues a record, or drops it when the sink cannot keep up. It does
+// nothing when auditing is disabled.
+func (a *auditLog) record(v interface{}) {
+	if a == nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		logger.Error("unable to marshal audit record", ari.LogError, err)
+		return
+	}
+	select {
+	case a.records <- append(b, '\n'):
+	default:
+		logger.Warn("audit trail is too slow, dropping record")
+	}
+}
+
+// busWriter publishes every write as a message on a bus topic.
+type busWriter chan []byte
+
+func (w busWriter) Write(b []byte) (int, error) {
+	w <- b
+	return len(b), nil
+}
+
+// rotatingFile is a file which is rotated once it reaches maxSize bytes,
+// keeping up to maxBackups older files named <path>.1, <path>.2 and so on.
+// It is not safe for concurrent use.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+// openRotatingFile opens a rotating file for appending. A maxSize of zero
+// selects 100 MB.
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if maxSize <= 0 {
+		maxSize = 100 * 1024 * 1024
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	return r, r.open()
+}
+
+// open opens the current file.
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// Write appends to the current file, rotating it first if the write would
+// take it over its maximum size.
+func (r *rotatingFile) Write(b []byte) (int, error) {
+	if r.size > 0 && r.size+int64(len(b)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups by one, dropping the oldest, and starts a new
+// current file.
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	backup := func(n int) string { return r.path + "." + strconv.Itoa(n) }
+	if r.maxBackups > 0 {
+		os.Remove(backup(r.maxBackups))
+		for n := r.maxBackups - 1; n >= 1; n-- {
+			os.Rename(backup(n), backup(n+1))
+		}
+		os.Rename(r.path, backup(1))
+	} else {
+		os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// auditCommand records a processed command along with its latency, and
+// counts it for the dialog's CDR.
+func (p *proxyInstance) auditCommand(c ari.Command, status int, start time.Time) {
+	p.statsLock.Lock()
+	p.commands++
+	if status >= 400 {
+		p.commandsFailed++
+	}
+	p.statsLock.Unlock()
+	audit.record(auditRecord{
+		Time: time.Now(), Type: "command", DialogID: p.dialogID, Application: p.application,
+		UniqueID: c.UniqueID, Method: c.Method, URL: c.URL, StatusCode: status,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	})
+}
+
+// auditEvent records an event published to the dialog, and remembers its
+// channel for the dialog's CDR.
+func (p *proxyInstance) auditEvent(eventType string, channelID string) {
+	p.statsLock.Lock()
+	p.events++
+	known := channelID == ""
+	for _, c := range p.channels {
+		if c == channelID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		p.channels = append(p.channels, channelID)
+	}
+	p.statsLock.Unlock()
+	audit.record(auditRecord{
+		Time: time.Now(), Type: "event", DialogID: p.dialogID, Application: p.application,
+		ChannelID: channelID, EventType: eventType,
+	})
+}
+
+// auditTeardown records the end of the dialog and its CDR.
+func (p *proxyInstance) auditTeardown(reason string) {
+	end := time.Now()
+	audit.record(auditRecord{Time: end, Type: "teardown", DialogID: p.dialogID, Application: p.application, Reason: reason})
+	p.statsLock.Lock()
+	defer p.statsLock.Unlock()
+	audit.record(callDetailRecord{
+		Type:           "cdr",
+		DialogID:       p.dialogID,
+		Application:    p.application,
+		ServerID:       config.ServerID,
+		Start:          p.started,
+		End:            end,
+		Duration:       end.Sub(p.started).Seconds(),
+		Reason:         reason,
+		Channels:       append([]string{}, p.channels...),
+		Events:         p.events,
+		Commands:       p.commands,
+		CommandsFailed: p.commandsFailed,
+	})
+}
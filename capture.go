@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+	"sync"
+	"time"
+)
+
+// recorder saves the websocket frames to the capture file given by the
+// -record flag, or is nil when not recording.
+var recorder *captureWriter
+
+// captureFrame is a websocket frame in a capture file, which holds one JSON
+// encoded captureFrame per line.
+type captureFrame struct {
+	Time        time.Time       `json:"time"`
+	Application string          `json:"application"`
+	Frame       json.RawMessage `json:"frame"` // the ARI event as received
+}
+
+// captureWriter appends frames to a capture file.
+type captureWriter struct {
+	lock sync.Mutex
+	f    *os.File
+}
+
+// openCapture opens a capture file for appending.
+func openCapture(path string) (*captureWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &captureWriter{f: f}, nil
+}
+
+// record saves a frame received on the websocket of an application. It does
+// nothing when not recording.
+func (w *captureWriter) record(application string, frame string) {
+	if w == nil {
+		return
+	}
+	line, err := json.Marshal(captureFrame{Time: time.Now(), Application: application, Frame: json.RawMessage(frame)})
+	if err != nil {
+		logger.Error("unable to record frame", ari.LogApplication, application, ari.LogError, err)
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, err := w.f.Write(append(line, '\n')); err != nil {
+		logger.Error("unable to record frame", ari.LogApplication, application, ari.LogError, err)
+	}
+}
+
+// runReplay feeds the frames of a capture file through PublishMessage, as if
+// they arrived on the websockets, spacing them as they were recorded divided
+// by the -speed factor. A speed of 0 replays the frames back to back. The
+// frames of each application are queued to dispatchEvents, as the live frames
+// are, so that a replay routes them the same way as they were routed live.
+// They are published with the application's producer, which is created for
+// applications that are not configured. runReplay returns once every frame
+// is published.
+func runReplay(args []string, producers map[string]chan []byte) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 1, "Replay speed factor, 0 for no delays")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *speed < 0 {
+		return fmt.Errorf("usage: replay [-speed factor] <capture file>")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	logger.Info("replaying capture", "file", fs.Arg(0), "speed", *speed)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var first, start time.Time
+	frames := 0
+	queues := make(map[string]chan arrival)
+	var dispatching sync.WaitGroup
+	defer func() {
+		for _, arrivals := range queues {
+			close(arrivals)
+		}
+		dispatching.Wait()
+	}()
+	for line := 1; scanner.Scan(); line++ {
+		var c captureFrame
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return fmt.Errorf("%s:%d: %s", fs.Arg(0), line, err)
+		}
+		if first.IsZero() {
+			first, start = c.Time, time.Now()
+		}
+		if *speed > 0 {
+			offset := time.Duration(float64(c.Time.Sub(first)) / *speed)
+			time.Sleep(time.Until(start.Add(offset)))
+		}
+		arrivals, ok := queues[c.Application]
+		if !ok {
+			producer, ok := producers[c.Application]
+			if !ok {
+				producer = ari.InitProducer(c.Application)
+				producers[c.Application] = producer
+			}
+			arrivals = make(chan arrival, 256)
+			queues[c.Application] = arrivals
+			dispatching.Add(1)
+			go func() {
+				defer dispatching.Done()
+				dispatchEvents(arrivals, producer)
+			}()
+		}
+		ctx, span := tracer.Start(context.Background(), "replay event",
+			trace.WithAttributes(attribute.String(ari.LogApplication, c.Application)))
+		arrivals <- arrival{ctx: ctx, span: span, message: string(c.Frame)}
+		frames++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	logger.Info("replay finished", "frames", frames)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCapture writes the frames of an application's channel to a capture
+// file and returns its path.
+func writeCapture(t *testing.T, application string, frames ...map[string]interface{}) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "capture")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	for _, frame := range frames {
+		frame["application"] = application
+		raw, _ := json.Marshal(frame)
+		if err = encoder.Encode(captureFrame{Time: time.Now(), Application: application, Frame: raw}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestReplayOrder(t *testing.T) {
+	channel := map[string]interface{}{"id": "replay-1", "name": "PJSIP/replay-00000001", "state": "Up"}
+	const digits = "0123456789*#ABCD"
+	frames := []map[string]interface{}{{"type": "StasisStart", "channel": channel}}
+	for _, digit := range digits {
+		frames = append(frames, map[string]interface{}{"type": "ChannelDtmfReceived", "channel": channel, "digit": string(digit)})
+	}
+	producers := map[string]chan []byte{}
+	if err := runReplay([]string{"-speed", "0", writeCapture(t, "replayed", frames...)}, producers); err != nil {
+		t.Fatal(err)
+	}
+	ai := waitInstance(t, "replayed")
+	waitEvent(t, ai, "StasisStart")
+	expectDigits(t, ai, digits)
+
+	end := writeCapture(t, "replayed", map[string]interface{}{"type": "StasisEnd", "channel": channel})
+	if err := runReplay([]string{"-speed", "0", end}, producers); err != nil {
+		t.Fatal(err)
+	}
+	waitEnded(t, "replay-1")
+}
@@ -0,0 +1,304 @@
+// ari-load drives a proxy with concurrent simulated calls and reports the
+// latencies and losses its applications see, to size proxies before a
+// campaign.
+//
+// Calls are originated through ARI into a Stasis application, and every
+// dialog runs a scripted application through the go-ari-library: it answers
+// the channel, plays a media and hangs up. With -fake, the load tool serves a
+// fake ARI for the proxy to connect to instead of driving Asterisk.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"github.com/nvisibleinc/go-ari-proxy/aritest"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config holds the settings the load tool reads from the configuration file
+// of the proxy, so that both share the ARI and the message bus.
+type Config struct {
+	Applications []string    `json:"applications"`
+	StasisURL    string      `json:"stasis_url"`
+	WSUser       string      `json:"ws_user"`
+	WSPassword   string      `json:"ws_password"`
+	MessageBus   string      `json:"message_bus"`
+	BusConfig    interface{} `json:"bus_config"`
+	Encoding     string      `json:"encoding"`
+}
+
+// call is a simulated call, which is done once its dialog has run the
+// script.
+type call struct {
+	id         string // channel ID the call is originated with
+	originated time.Time
+	done       chan bool
+}
+
+var (
+	config      Config
+	application string
+	logger      = slog.Default()
+	results     = new(stats)
+	pendingLock sync.Mutex
+	pending     = make(map[string]*call) // calls waiting for their dialog, by channel ID
+
+	configPath  = flag.String("config", "./config.json", "Path to the proxy configuration file")
+	app         = flag.String("app", "", "Application to call, the first configured one by default")
+	calls       = flag.Int("calls", 100, "Number of calls to place")
+	concurrency = flag.Int("concurrency", 10, "Number of calls in progress at a time")
+	rate        = flag.Float64("rate", 0, "Calls placed per second, 0 for no limit")
+	endpoint    = flag.String("endpoint", "Local/load@default", "Endpoint the calls are originated to")
+	media       = flag.String("media", "sound:hello-world", "Media played on every call")
+	hold        = flag.Duration("hold", 0, "How long calls stay up after the playback")
+	timeout     = flag.Duration("timeout", 10*time.Second, "Longest wait for a dialog or an event")
+	fake        = flag.Bool("fake", false, "Serve a fake ARI at the stasis_url for the proxy to connect to")
+)
+
+// fatal logs an error and exits.
+func fatal(msg string, args ...interface{}) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+func main() {
+	flag.Parse()
+	configfile, err := ioutil.ReadFile(*configPath)
+	if err != nil {
+		fatal("unable to read the configuration", ari.LogError, err)
+	}
+	if err = json.Unmarshal(configfile, &config); err != nil {
+		fatal("unable to parse the configuration", ari.LogError, err)
+	}
+	application = *app
+	if application == "" && len(config.Applications) > 0 {
+		application = config.Applications[0]
+	}
+	if application == "" {
+		fatal("no application to call")
+	}
+	if config.MessageBus == "MEMORY" {
+		fatal("the MEMORY bus does not reach a proxy in another process")
+	}
+	if *concurrency < 1 {
+		fatal("concurrency must be at least 1")
+	}
+	if err = ari.SetEncoding(config.Encoding); err != nil {
+		fatal("invalid encoding", ari.LogError, err)
+	}
+	if err = ari.InitBus(config.MessageBus, config.BusConfig); err != nil {
+		fatal("unable to initialize the message bus", "bus", config.MessageBus, ari.LogError, err)
+	}
+	if *fake {
+		s, err := serveFake()
+		if err != nil {
+			fatal("unable to serve the fake ARI", ari.LogError, err)
+		}
+		defer s.Close()
+	}
+
+	ari.NewApp().Init(application, runDialog)
+	logger.Info("placing calls", ari.LogApplication, application, "calls", *calls, "concurrency", *concurrency)
+	start := time.Now()
+	placeCalls()
+	elapsed := time.Since(start)
+	results.report(os.Stdout, elapsed)
+}
+
+// serveFake serves a fake ARI at the host of the stasis_url and waits for
+// the proxy to connect to it.
+func serveFake() (*aritest.Server, error) {
+	u, err := url.Parse(config.StasisURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Path != "/ari" {
+		return nil, fmt.Errorf("the fake ARI is served under /ari, not %s", u.Path)
+	}
+	s, err := aritest.NewServerAt(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	s.APIKey = strings.Join([]string{config.WSUser, ":", config.WSPassword}, "")
+	logger.Info("waiting for the proxy to connect", "stasis_url", config.StasisURL)
+	if err = s.WaitConnected(application, time.Minute); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// running counts the calls being placed and the dialogs still taking the
+// events of their ended call off the bus, which the report waits for.
+var running sync.WaitGroup
+
+// placeCalls places the calls, keeping up to the concurrency in progress at
+// the configured rate, and waits for all of them to end.
+func placeCalls() {
+	slots := make(chan bool, *concurrency)
+	var interval time.Duration
+	if *rate > 0 {
+		interval = time.Duration(float64(time.Second) / *rate)
+	}
+	for i := 0; i < *calls; i++ {
+		slots <- true
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			placeCall()
+			<-slots
+		}()
+		time.Sleep(interval)
+	}
+	running.Wait()
+}
+
+// placeCall originates a call and waits for its dialog to run the script.
+func placeCall() {
+	c := &call{id: ari.UUID(), originated: time.Now(), done: make(chan bool)}
+	pendingLock.Lock()
+	pending[c.id] = c
+	pendingLock.Unlock()
+
+	if err := originate(c.id); err != nil {
+		logger.Warn("unable to originate call", ari.LogChannelID, c.id, ari.LogError, err)
+		claim(c.id)
+		results.count(&results.originateFailed)
+		return
+	}
+	results.count(&results.originated)
+	select {
+	case <-c.done:
+	case <-time.After(*timeout):
+		if claim(c.id) != nil {
+			// no dialog claimed the call in time
+			results.count(&results.noAppStart)
+			return
+		}
+		<-c.done
+	}
+	results.count(&results.completed)
+}
+
+// claim removes a call from the calls waiting for their dialog, and returns
+// it unless it was claimed already.
+func claim(id string) *call {
+	pendingLock.Lock()
+	defer pendingLock.Unlock()
+	c, ok := pending[id]
+	if !ok {
+		return nil
+	}
+	delete(pending, id)
+	return c
+}
+
+// originate asks ARI for a channel to the endpoint, which enters the Stasis
+// application once answered.
+func originate(id string) error {
+	q := url.Values{}
+	q.Set("endpoint", *endpoint)
+	q.Set("app", application)
+	q.Set("channelId", id)
+	q.Set("api_key", strings.Join([]string{config.WSUser, ":", config.WSPassword}, ""))
+	res, err := http.Post(strings.Join([]string{config.StasisURL, "/channels?", q.Encode()}, ""), "application/json", nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("ARI answered %s", res.Status)
+	}
+	return nil
+}
+
+// runDialog runs the script of a call on its dialog: it answers the channel,
+// plays the media, holds the call and hangs it up. Dialogs of channels the
+// load tool did not originate are left alone, and their topics released.
+func runDialog(ai *ari.AppInstance) {
+	appStarted := time.Now()
+	e, ok := waitEvent(ai, "StasisStart")
+	if !ok {
+		ai.Close()
+		return
+	}
+	var start ari.StasisStart
+	json.Unmarshal(e.ARI_Body, &start)
+	c := claim(start.Channel.Id)
+	if c == nil {
+		ai.Close()
+		return
+	}
+	defer close(c.done)
+	results.observe(&results.appStart, appStarted.Sub(c.originated))
+
+	id := c.id
+	command(func() error { return ai.ChannelsAnswer(id) })
+	if command(func() error { _, err := ai.ChannelsPlay(id, *media); return err }) {
+		if _, ok := waitEvent(ai, "PlaybackStarted"); ok {
+			waitEvent(ai, "PlaybackFinished")
+		}
+	}
+	time.Sleep(*hold)
+	command(func() error { return ai.ChannelsHangup(id) })
+
+	// keep taking the events of the ended call off the bus until its dialog
+	// ends, then release the dialog's topics. The call only ends once c.done
+	// is closed, so running cannot drop to zero before this is counted.
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		waitEvent(ai, "StasisEnd")
+		ai.Close()
+	}()
+}
+
+// waitEvent waits for an event of the given type on a dialog, observing the
+// delivery latency of every event received meanwhile. It returns false, and
+// counts the event as dropped, if none arrives in time.
+func waitEvent(ai *ari.AppInstance, eventType string) (*ari.Event, bool) {
+	deadline := time.After(*timeout)
+	for {
+		select {
+		case e := <-ai.Events:
+			// the proxy stamps events as they arrive from ARI
+			results.observe(&results.event, time.Since(e.Timestamp))
+			if e.Type == eventType {
+				return e, true
+			}
+		case <-deadline:
+			results.count(&results.eventsDropped)
+			return nil, false
+		}
+	}
+}
+
+// command sends a command of the script and observes its round trip. It
+// reports whether the command succeeded. Commands which got no response
+// are counted as dropped, as the library gives up on them after
+// ari.CommandTimeout.
+func command(send func() error) bool {
+	start := time.Now()
+	err := send()
+	rtt := time.Since(start)
+	switch {
+	case rtt >= ari.CommandTimeout:
+		results.count(&results.commandsDropped)
+		return false
+	case err != nil:
+		results.observe(&results.command, rtt)
+		results.count(&results.commandsFailed)
+		return false
+	}
+	results.observe(&results.command, rtt)
+	return true
+}
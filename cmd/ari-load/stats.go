@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// stats collects the latencies and counts of a load run.
+type stats struct {
+	lock            sync.Mutex
+	appStart        []time.Duration // from the origination of a call to the AppStart of its dialog
+	event           []time.Duration // from the arrival of an event at the proxy to the application
+	command         []time.Duration // from sending a command to receiving its response
+	originated      int
+	originateFailed int
+	completed       int
+	noAppStart      int // calls whose dialog did not start in time
+	eventsDropped   int // events the script waited for in vain
+	commandsFailed  int
+	commandsDropped int // commands without a response
+}
+
+// observe adds a latency to a series.
+func (s *stats) observe(series *[]time.Duration, d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	*series = append(*series, d)
+}
+
+// count increments a counter.
+func (s *stats) count(counter *int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	*counter++
+}
+
+// report writes the counts and the latency percentiles of the run.
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fmt.Fprintf(w, "calls: %d originated, %d failed to originate, %d completed, %d without AppStart\n",
+		s.originated, s.originateFailed, s.completed, s.noAppStart)
+	fmt.Fprintf(w, "dropped: %d events, %d commands\n", s.eventsDropped, s.commandsDropped)
+	fmt.Fprintf(w, "failed: %d commands\n\n", s.commandsFailed)
+
+	fmt.Fprintf(w, "%-12s %8s %8s %8s %8s %8s\n", "latency (ms)", "count", "p50", "p90", "p99", "max")
+	for _, series := range []struct {
+		name    string
+		latency []time.Duration
+	}{
+		{"AppStart", s.appStart},
+		{"event", s.event},
+		{"command", s.command},
+	} {
+		sorted := append([]time.Duration{}, series.latency...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Fprintf(w, "%-12s %8d %8s %8s %8s %8s\n", series.name, len(sorted),
+			millis(percentile(sorted, 0.5)), millis(percentile(sorted, 0.9)),
+			millis(percentile(sorted, 0.99)), millis(percentile(sorted, 1)))
+	}
+	fmt.Fprintf(w, "\n%d calls completed in %s, %.1f calls/s\n", s.completed, elapsed.Round(time.Millisecond),
+		float64(s.completed)/elapsed.Seconds())
+}
+
+// percentile returns the p-th percentile, between 0 and 1, of sorted
+// latencies, or zero when there are none.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// millis formats a latency in milliseconds.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1f", float64(d.Microseconds())/1000)
+}
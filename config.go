@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// loadConfig reads the configuration file into config, validates it and, once
+// valid, applies it. It returns every problem found rather than stopping at
+// the first one.
+func loadConfig(path string) []error {
+	logger.Info("loading configuration", "path", path)
+	configfile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+	if errs := decodeConfig(configfile, &config); len(errs) > 0 {
+		return errs
+	}
+	if errs := checkConfig(&config); len(errs) > 0 {
+		return errs
+	}
+	return applyConfig()
+}
+
+// decodeConfig decodes the configuration into c section by section, so that
+// settings of the wrong type are reported for each section, and reports the
+// settings Config has no field for. The bus_config section is left to the
+// checks of the message bus.
+func decodeConfig(data []byte, c *Config) []error {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := position(data, syntaxErr.Offset)
+			return []error{fmt.Errorf("line %d, column %d: %s", line, column, err)}
+		}
+		return []error{errors.New("the configuration must be a JSON object")}
+	}
+	fields := jsonFields(reflect.TypeOf(*c))
+	var errs []error
+	for _, key := range ari.SortedKeys(sections) {
+		field, ok := fields[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown setting %q", key))
+			continue
+		}
+		for _, path := range unknownFields(key, sections[key], field.Type) {
+			errs = append(errs, fmt.Errorf("unknown setting %q", path))
+		}
+		v := reflect.ValueOf(c).Elem().FieldByIndex(field.Index)
+		if err := json.Unmarshal(sections[key], v.Addr().Interface()); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				path := key
+				if typeErr.Field != "" {
+					path = strings.Join([]string{key, typeErr.Field}, ".")
+				}
+				err = fmt.Errorf("%s: got %s, want %s", path, typeErr.Value, typeErr.Type)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// unknownFields returns the paths of the settings in data that the type it
+// is decoded into has no field for.
+func unknownFields(path string, data json.RawMessage, t reflect.Type) []string {
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownFields(path, data, t.Elem())
+	case reflect.Struct:
+		var m map[string]json.RawMessage
+		if json.Unmarshal(data, &m) != nil {
+			// not an object, which decoding reports
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range ari.SortedKeys(m) {
+			p := strings.Join([]string{path, key}, ".")
+			field, ok := fields[key]
+			if !ok {
+				unknown = append(unknown, p)
+				continue
+			}
+			unknown = append(unknown, unknownFields(p, m[key], field.Type)...)
+		}
+	case reflect.Map:
+		var m map[string]json.RawMessage
+		if json.Unmarshal(data, &m) != nil {
+			return nil
+		}
+		for _, key := range ari.SortedKeys(m) {
+			unknown = append(unknown, unknownFields(strings.Join([]string{path, key}, "."), m[key], t.Elem())...)
+		}
+	case reflect.Slice:
+		var s []json.RawMessage
+		if json.Unmarshal(data, &s) != nil {
+			return nil
+		}
+		for i, element := range s {
+			unknown = append(unknown, unknownFields(fmt.Sprintf("%s[%d]", path, i), element, t.Elem())...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields maps the JSON names of the fields of a struct type to the
+// fields.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			fields[name] = f
+		}
+	}
+	return fields
+}
+
+// position returns the line and column of an offset in data.
+func position(data []byte, offset int64) (int, int) {
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// checkConfig validates a decoded configuration. It changes neither the
+// configuration nor the settings of the library, which applyConfig does.
+func checkConfig(c *Config) []error {
+	var errs []error
+	add := func(err error) {
+		if err == nil {
+			return
+		}
+		// report the problems of joined errors one by one
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = append(errs, joined.Unwrap()...)
+			return
+		}
+		errs = append(errs, err)
+	}
+
+	if len(c.Applications) == 0 {
+		add(errors.New("applications: at least one application is required"))
+	}
+	seen := make(map[string]bool)
+	for _, app := range c.Applications {
+		switch {
+		case app == "":
+			add(errors.New("applications: application names cannot be empty"))
+		case seen[app]:
+			add(fmt.Errorf("applications: %s is listed twice", app))
+		}
+		seen[app] = true
+	}
+	add(ari.CheckURL("websocket_url", c.WebsocketURL, "ws", "wss"))
+	if u, err := url.Parse(c.WebsocketURL); err == nil && u.RawQuery != "" {
+		// the application and credentials are added as the query
+		add(errors.New("websocket_url: cannot have a query"))
+	}
+	add(ari.CheckURL("stasis_url", c.StasisURL, "http", "https"))
+	add(ari.CheckURL("origin", c.Origin, "http", "https"))
+	if c.MessageBus == "" {
+		add(errors.New("message_bus is required"))
+	} else {
+		add(ari.CheckBusConfig(c.MessageBus, c.BusConfig))
+	}
+
+	if err := ari.CheckEncoding(c.Encoding); err != nil {
+		add(fmt.Errorf("encoding: %s", err))
+	}
+	if c.EventVersion < 0 || c.EventVersion > ari.EnvelopeVersion {
+		add(fmt.Errorf("event_version: unsupported version %d", c.EventVersion))
+	}
+	if c.EventVersion == 1 && c.Encoding != "" && c.Encoding != "json" {
+		// only JSON encoded bus messages predate version 2
+		add(errors.New("event_version: version 1 requires the json encoding"))
+	}
+	if c.Compression.Algorithm != "" && c.EventVersion == 1 {
+		// applications that only understand version 1 cannot decompress
+		add(errors.New("compression: requires event_version 2"))
+	}
+	if err := ari.CheckCompression(c.Compression.Algorithm, c.Compression.Threshold); err != nil {
+		add(fmt.Errorf("compression: %s", err))
+	}
+	if c.Compression.MaxSize < 0 {
+		add(fmt.Errorf("compression: invalid max_size %d", c.Compression.MaxSize))
+	}
+	if _, err := loadKeys(c.Security); err != nil {
+		add(err)
+	}
+	if len(c.Security) > 0 && c.EventVersion == 1 {
+		// signatures and encryption cover the version 2 envelope
+		add(errors.New("security: requires event_version 2"))
+	}
+	add(checkPolicy(c.Policy))
+	add(checkRateLimits(c.RateLimits))
+	add(checkAdmission(c.Admission))
+
+	if c.Logging.Level != "" {
+		if _, err := ari.ParseLevel(c.Logging.Level); err != nil {
+			add(fmt.Errorf("logging: %s", err))
+		}
+	}
+	if _, err := ari.NewLogger(io.Discard, c.Logging.Format, logLevel); err != nil {
+		add(fmt.Errorf("logging: %s", err))
+	}
+	switch c.Tracing.Exporter {
+	case "", "otlp", "stdout":
+	case "file":
+		if c.Tracing.File == "" {
+			add(errors.New("tracing: the file exporter needs a file"))
+		}
+	default:
+		add(fmt.Errorf("tracing: unknown exporter %q", c.Tracing.Exporter))
+	}
+	if r := c.Tracing.SampleRatio; r != nil && (*r < 0 || *r > 1) {
+		add(fmt.Errorf("tracing: sample_ratio %v is not between 0 and 1", *r))
+	}
+	switch c.Audit.Output {
+	case "", "bus":
+	case "file":
+		if c.Audit.File == "" {
+			add(errors.New("audit: the file output needs a file"))
+		}
+	default:
+		add(fmt.Errorf("audit: invalid output %q", c.Audit.Output))
+	}
+
+	for _, l := range []struct{ section, address string }{
+		{"gateway", c.Gateway.Listen},
+		{"grpc", c.GRPC.Listen},
+		{"monitoring", c.Monitoring.Listen},
+		{"admin", c.Admin.Listen},
+	} {
+		if l.address == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(l.address); err != nil {
+			add(fmt.Errorf("%s: invalid listen address %q", l.section, l.address))
+		}
+	}
+	return errs
+}
+
+// applyConfig applies the settings of a valid configuration: the encoding and
+// compression of bus messages, and the keys of the applications. A
+// compression without a threshold compresses payloads of 4096 bytes or more,
+// and payloads decompress to at most 16 MiB unless max_size is set.
+func applyConfig() []error {
+	if config.Compression.Algorithm != "" && config.Compression.Threshold == 0 {
+		config.Compression.Threshold = 4096
+	}
+	if config.Compression.MaxSize == 0 {
+		config.Compression.MaxSize = ari.DefaultDecompressionLimit
+	}
+	var errs []error
+	if err := ari.SetEncoding(config.Encoding); err != nil {
+		errs = append(errs, fmt.Errorf("encoding: %s", err))
+	}
+	if err := ari.SetCompression(config.Compression.Algorithm, config.Compression.Threshold); err != nil {
+		errs = append(errs, fmt.Errorf("compression: %s", err))
+	}
+	if err := ari.SetDecompressionLimit(config.Compression.MaxSize); err != nil {
+		errs = append(errs, fmt.Errorf("compression: %s", err))
+	}
+	keys, err := loadKeys(config.Security)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	appKeys = keys
+	return errs
+}
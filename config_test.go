@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/nvisibleinc/go-ari-library"
+	"reflect"
+	"testing"
+)
+
+// validSettings returns the settings of a minimal valid configuration file.
+func validSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"server_id":     "test",
+		"applications":  []string{"app"},
+		"websocket_url": "ws://localhost:8088/ari/events",
+		"stasis_url":    "http://localhost:8088/ari",
+		"origin":        "http://localhost/",
+		"message_bus":   "MEMORY",
+	}
+}
+
+// validate decodes and checks a configuration file holding the valid
+// settings with the changes given into c, and returns the problems reported.
+func validate(t *testing.T, c *Config, changes map[string]interface{}) []string {
+	t.Helper()
+	settings := validSettings()
+	for key, value := range changes {
+		settings[key] = value
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := decodeConfig(data, c)
+	if len(errs) == 0 {
+		errs = checkConfig(c)
+	}
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestCheckConfig(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		changes map[string]interface{}
+		want    []string
+	}{
+		{"valid", nil, nil},
+		{"unknown setting", map[string]interface{}{"websocket": "ws://localhost"},
+			[]string{`unknown setting "websocket"`}},
+		{"unknown nested setting", map[string]interface{}{"grpc": map[string]interface{}{"listen": ":9090", "token": "secret"}},
+			[]string{`unknown setting "grpc.token"`}},
+		{"unknown setting of a map entry", map[string]interface{}{"security": map[string]interface{}{"app": map[string]interface{}{"hmac_key": "secret"}}},
+			[]string{`unknown setting "security.app.hmac_key"`}},
+		{"type error", map[string]interface{}{"applications": "app"},
+			[]string{"applications: got string, want []string"}},
+		{"nested type error", map[string]interface{}{"compression": map[string]interface{}{"threshold": "4k"}},
+			[]string{"compression.threshold: got string, want int"}},
+		{"problems of every section", map[string]interface{}{"origin": 1, "audit": map[string]interface{}{"outputs": "file"}}, []string{
+			`unknown setting "audit.outputs"`,
+			"origin: got number, want string",
+		}},
+		{"missing url", map[string]interface{}{"stasis_url": ""},
+			[]string{"stasis_url is required"}},
+		{"wrong scheme", map[string]interface{}{"websocket_url": "http://localhost:8088/ari/events"},
+			[]string{`websocket_url: invalid URL "http://localhost:8088/ari/events", want a ws:// or wss:// URL`}},
+		{"websocket query", map[string]interface{}{"websocket_url": "ws://localhost:8088/ari/events?app=app"},
+			[]string{"websocket_url: cannot have a query"}},
+		{"bus problems one by one", map[string]interface{}{"bus_config": map[string]interface{}{"buffer": "8", "size": 8}}, []string{
+			`memory: invalid buffer: got string, want int`,
+			`memory: unknown setting "size"`,
+		}},
+		{"unknown bus", map[string]interface{}{"message_bus": "CARRIER_PIGEON"},
+			[]string{`unknown message bus "CARRIER_PIGEON"`}},
+		{"unknown encoding", map[string]interface{}{"encoding": "xml"},
+			[]string{`encoding: unknown encoding "xml"`}},
+		{"version 1 encoding", map[string]interface{}{"event_version": 1, "encoding": "cbor"},
+			[]string{"event_version: version 1 requires the json encoding"}},
+		{"unknown compression", map[string]interface{}{"compression": map[string]interface{}{"algorithm": "lzma"}},
+			[]string{`compression: unknown compression "lzma"`}},
+		{"negative decompression limit", map[string]interface{}{"compression": map[string]interface{}{"max_size": -1}},
+			[]string{"compression: invalid max_size -1"}},
+	} {
+		if got := validate(t, new(Config), test.changes); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got errors %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCheckConfigAppliesNothing(t *testing.T) {
+	keys := appKeys
+	var c Config
+	errs := validate(t, &c, map[string]interface{}{
+		"encoding":    "cbor",
+		"compression": map[string]interface{}{"algorithm": "zstd"},
+		"security":    map[string]interface{}{"app": map[string]interface{}{"hmac_keys": map[string]string{"1": "secret"}}},
+	})
+	if errs != nil {
+		t.Fatalf("the configuration is invalid: %q", errs)
+	}
+	if ari.ContentType() != "application/json" {
+		t.Errorf("checking selected the encoding %s", ari.ContentType())
+	}
+	if c.Compression.Threshold != 0 {
+		t.Errorf("checking set the compression threshold to %d", c.Compression.Threshold)
+	}
+	if !reflect.DeepEqual(appKeys, keys) {
+		t.Error("checking loaded the keys of the applications")
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nvisibleinc/go-ari-library"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"sync"
+)
+
+// gateway fans AppStarts and dialog events out to websocket clients, and
+// accepts Commands from them, for clients that cannot use the message bus.
+// It is an eventTap.
+type gateway struct {
+	lock    sync.RWMutex
+	clients map[*gatewayClient]bool
+}
+
+// gatewayClient is a single authenticated websocket connection and the
+// applications and dialogs it has subscribed to.
+type gatewayClient struct {
+	ws           *websocket.Conn
+	applications map[string]bool // applications the client's token allows
+	lock         sync.RWMutex
+	appSubs      map[string]bool
+	dialogSubs   map[string]bool
+	send         chan []byte
+	closed       bool
+}
+
+// gatewayRequest is a message sent by a client. Action is one of "subscribe",
+// "unsubscribe" or "command". Subscriptions name either an Application, for
+// its AppStarts, or a DialogID, for its events.
+type gatewayRequest struct {
+	Action      string      `json:"action"`
+	Application string      `json:"application,omitempty"`
+	DialogID    string      `json:"dialog_id,omitempty"`
+	Command     ari.Command `json:"command"`
+}
+
+// gatewayMessage is a message sent to a client. Type is one of "app_start",
+// "event", "command_response" or "error", and the matching field is set.
+type gatewayMessage struct {
+	Type            string               `json:"type"`
+	DialogID        string               `json:"dialog_id,omitempty"`
+	AppStart        *ari.AppStart        `json:"app_start,omitempty"`
+	Event           *ari.Event           `json:"event,omitempty"`
+	CommandResponse *ari.CommandResponse `json:"command_response,omitempty"`
+	Error           string               `json:"error,omitempty"`
+}
+
+// runGateway serves the websocket gateway until the listener fails.
+func runGateway(g *gateway) {
+	path := config.Gateway.Path
+	if path == "" {
+		path = "/ws"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, websocket.Server{
+		// clients are authenticated by token, so accept any origin
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   g.serveClient,
+	})
+	logger.Info("starting websocket gateway", "listen", config.Gateway.Listen, "path", path)
+	err := http.ListenAndServe(config.Gateway.Listen, mux)
+	fatal("unable to serve the websocket gateway", ari.LogError, err)
+}
+
+// newGateway initializes an empty gateway.
+func newGateway() *gateway {
+	return &gateway{clients: make(map[*gatewayClient]bool)}
+}
+
+// serveClient authenticates a new connection and processes its requests
+// until it goes away. The token is passed as the "token" query parameter, as
+// browsers cannot set headers on websocket connections.
+func (g *gateway) serveClient(ws *websocket.Conn) {
+	defer ws.Close()
+	apps, ok := config.Gateway.Tokens[ws.Request().URL.Query().Get("token")]
+	if !ok {
+		websocket.JSON.Send(ws, gatewayMessage{Type: "error", Error: "invalid token"})
+		return
+	}
+
+	c := &gatewayClient{
+		ws:           ws,
+		applications: make(map[string]bool),
+		appSubs:      make(map[string]bool),
+		dialogSubs:   make(map[string]bool),
+		send:         make(chan []byte, 256),
+	}
+	for _, app := range apps {
+		c.applications[app] = true
+	}
+	g.lock.Lock()
+	g.clients[c] = true
+	g.lock.Unlock()
+	defer func() {
+		g.lock.Lock()
+		delete(g.clients, c)
+		g.lock.Unlock()
+		c.lock.Lock()
+		c.closed = true
+		close(c.send)
+		c.lock.Unlock()
+	}()
+
+	go c.writeLoop()
+	for {
+		var req gatewayRequest
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			logger.Debug("gateway client went away", "client", ws.Request().RemoteAddr, ari.LogError, err)
+			return
+		}
+		c.handle(req)
+	}
+}
+
+// publishAppStart sends an AppStart to every client subscribed to its
+// application.
+func (g *gateway) publishAppStart(as ari.AppStart) {
+	m, _ := json.Marshal(gatewayMessage{Type: "app_start", DialogID: as.DialogID, AppStart: &as})
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	for c := range g.clients {
+		c.lock.RLock()
+		subscribed := c.appSubs[as.Application]
+		c.lock.RUnlock()
+		if subscribed {
+			c.queue(m)
+		}
+	}
+}
+
+// publishEvent sends an Event to every client subscribed to its dialog.
+func (g *gateway) publishEvent(dialogID string, e *ari.Event) {
+	m, _ := json.Marshal(gatewayMessage{Type: "event", DialogID: dialogID, Event: e})
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	for c := range g.clients {
+		c.lock.RLock()
+		subscribed := c.dialogSubs[dialogID]
+		c.lock.RUnlock()
+		if subscribed {
+			c.queue(m)
+		}
+	}
+}
+
+// handle processes a single request from the client.
+func (c *gatewayClient) handle(req gatewayRequest) {
+	var pi *proxyInstance
+	if req.DialogID != "" {
+		var exists bool
+		pi, exists = proxyInstances.GetDialog(req.DialogID)
+		if !exists || !c.applications[pi.application] {
+			c.sendError(req.DialogID, "unknown dialog")
+			return
+		}
+	} else if !c.applications[req.Application] {
+		c.sendError("", "application not allowed")
+		return
+	}
+
+	switch req.Action {
+	case "subscribe", "unsubscribe":
+		c.lock.Lock()
+		if req.DialogID != "" {
+			c.dialogSubs[req.DialogID] = req.Action == "subscribe"
+		} else {
+			c.appSubs[req.Application] = req.Action == "subscribe"
+		}
+		c.lock.Unlock()
+
+	case "command":
+		if pi == nil {
+			c.sendError("", "commands need a dialog_id")
+			return
+		}
+		responses := make(chan []byte)
+		go pi.processCommand(context.Background(), req.Command, responses, pi.admitCommand(req.Command))
+		go func(dialogID string) {
+			var r ari.CommandResponse
+			ari.Unmarshal(<-responses, &r)
+			m, _ := json.Marshal(gatewayMessage{Type: "command_response", DialogID: dialogID, CommandResponse: &r})
+			c.queue(m)
+		}(req.DialogID)
+
+	default:
+		c.sendError(req.DialogID, "unknown action")
+	}
+}
+
+// queue hands a message to the client's writer. Clients that cannot keep up
+// are disconnected rather than holding up the proxy.
+func (c *gatewayClient) queue(m []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		// the client went away since it was looked up
+		return
+	}
+	select {
+	case c.send <- m:
+	default:
+		logger.Warn("gateway client is too slow, disconnecting", "client", c.ws.Request().RemoteAddr)
+		c.ws.Close()
+	}
+}
+
+// sendError reports a failed request to the client.
+func (c *gatewayClient) sendError(dialogID string, e string) {
+	m, _ := json.Marshal(gatewayMessage{Type: "error", DialogID: dialogID, Error: e})
+	c.queue(m)
+}
+
+// writeLoop writes queued messages to the websocket until the client goes
+// away.
+func (c *gatewayClient) writeLoop() {
+	for m := range c.send {
+		if err := websocket.Message.Send(c.ws, string(m)); err != nil {
+			c.ws.Close()
+			return
+		}
+	}
+}
@@ -5,121 +5,216 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"github.com/nvisibleinc/go-ari-library"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/websocket"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
 
 // Var config contains a Config struct to hold the proxy configuration file.
 var (
-	config         Config            // main proxy configuration structure
-	client         = &http.Client{}  // connection for Commands to ARI
-	proxyInstances *proxyInstanceMap // maps the per-dialog proxy instances
-	Debug          *log.Logger
-	Info           *log.Logger
-	Warning        *log.Logger
-	Error          *log.Logger
+	config          Config               // main proxy configuration structure
+	proxyInstanceID = ari.UUID()         // identifies this proxy process in message envelopes
+	client          = &http.Client{}     // connection for Commands to ARI
+	proxyInstances  *proxyInstanceMap    // maps the per-dialog proxy instances
+	appKeys         map[string]*ari.Keys // keys by application
+	taps            []eventTap           // frontends that receive AppStarts and events besides the bus
+	logger          *slog.Logger         // structured logger of the proxy
+	logLevel        = new(slog.LevelVar) // level of the logger, adjustable at runtime
 )
 
 // signalCatcher is a function to allows us to stop the application through an
 // operating system signal.
 func signalCatcher() {
-	ch := make(chan os.Signal)
+	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT)
 	sig := <-ch
-	log.Printf("Signal received: %v", sig)
+	logger.Info("signal received", "signal", sig)
+	shutdownTracing(context.Background())
 	os.Exit(0)
 }
 
-// Init parses the configuration file by unmarshaling it into a Config struct.
+// Init sets up the default logger and the proxy instance map, which the
+// tests rely on as well.
 func init() {
-	var err error
-
-	// Setup our logging interfaces
-	Debug = ari.InitLogger(os.Stdout, "DEBUG")
-	Info = ari.InitLogger(os.Stdout, "INFO")
-	Warning = ari.InitLogger(os.Stdout, "WARNING")
-	Error = ari.InitLogger(os.Stderr, "ERROR")
+	// log as text at the info level until the configuration says otherwise
+	logger, _ = ari.NewLogger(os.Stdout, "text", logLevel)
+	proxyInstances = NewproxyInstanceMap() // initialize a new proxy instance map
+}
 
+func main() {
 	// parse the configuration file and get data from it
-	Info.Println("Loading configuration for proxy.")
 	configpath := flag.String("config", "./config.json", "Path to config file")
-
-	Debug.Println("Parsing the configuration file.")
+	recordPath := flag.String("record", "", "Path to a capture file to record the ARI websocket frames to")
+	checkOnly := flag.Bool("check-config", false, "Validate the configuration file and exit")
 	flag.Parse()
-	Debug.Println("Reading in the configuration from the file.")
-	configfile, err := ioutil.ReadFile(*configpath)
-	if err != nil {
-		Error.Fatal(err)
+	if errs := loadConfig(*configpath); len(errs) > 0 {
+		for _, err := range errs {
+			logger.Error("invalid configuration", ari.LogError, err)
+		}
+		os.Exit(1)
+	}
+	if *checkOnly {
+		logger.Info("configuration is valid", "path", *configpath)
+		os.Exit(0)
+	}
+	if err := configureLogging(); err != nil {
+		fatal("invalid logging configuration", ari.LogError, err)
+	}
+	if err := initTracing(); err != nil {
+		fatal("invalid tracing configuration", ari.LogError, err)
 	}
-	// read in the configuration file and unmarshal the json, storing it in 'config'
-	Debug.Println("Unmarshaling proxy configuration.")
-	json.Unmarshal(configfile, &config)
-	Debug.Println(&config)
-	Debug.Println("Initialize the proxy instance map.")
-	proxyInstances = NewproxyInstanceMap() // initialize a new proxy instance map
-}
 
-func main() {
-	// Setup a new Event producer and Command consumer for every application
-	// we've configured in the configuration file.
-	Info.Println("Initializing the message bus.")
-	ari.InitBus(config.MessageBus, config.BusConfig)
+	var err error
+	logger.Info("initializing the message bus", "bus", config.MessageBus)
+	ari.OnPublishError(countPublishError)
+	if err = ari.InitBus(config.MessageBus, config.BusConfig); err != nil {
+		fatal("unable to initialize the message bus", "bus", config.MessageBus, ari.LogError, err)
+	}
+	if err = initAudit(); err != nil {
+		fatal("invalid audit configuration", ari.LogError, err)
+	}
+	if *recordPath != "" {
+		if recorder, err = openCapture(*recordPath); err != nil {
+			fatal("unable to open the capture file", ari.LogError, err)
+		}
+	}
+	// the replay command feeds a capture file to the applications instead of
+	// the websockets
+	replay := flag.Arg(0) == "replay"
+	if flag.NArg() > 0 && !replay {
+		fatal("unknown command", "command", flag.Arg(0))
+	}
+	producers := make(map[string]chan []byte)
 	for _, app := range config.Applications {
 		/*
 			Create a new producer which is responsible for the initial topic on the message bus which is used
 			to signal the setup of per-application instances. All applications listen to this topic in order to
 			be provided the information to setup the ownership of per dialog application instances.
 		*/
-		Info.Printf("Initializing signalling bus for application %s", app)
+		logger.Info("initializing signalling bus", ari.LogApplication, app)
 		producer := ari.InitProducer(app) // Initialize a new producer channel using the ari.InitProducer function.
-		Info.Printf("Starting event handler for application %s", app)
+		producers[app] = producer
+		if replay {
+			continue
+		}
+		logger.Info("starting event handler", ari.LogApplication, app)
 		go runEventHandler(app, producer) // create new websocket connection for every application and pass the producer channel
 	}
 
+	if config.Gateway.Listen != "" {
+		g := newGateway()
+		taps = append(taps, g)
+		go runGateway(g)
+	}
+	if config.GRPC.Listen != "" {
+		g := newGRPCServer()
+		taps = append(taps, g)
+		go runGRPCServer(g)
+	}
+	if config.Monitoring.Listen != "" {
+		go runMonitoring()
+	}
+	if config.Admin.Listen != "" {
+		go runAdmin()
+	}
+
 	go signalCatcher() // listen for os signal to stop the application
+	if replay {
+		// keep serving the dialogs of the replay until stopped
+		if err = runReplay(flag.Args()[1:], producers); err != nil {
+			fatal("unable to replay the capture", ari.LogError, err)
+		}
+	}
 	select {}
 }
 
-// runEventHandler sets up a websocket connection to an ARI application.
+// runEventHandler sets up a websocket connection to an ARI application, and
+// reconnects with an increasing delay whenever the connection fails.
 func runEventHandler(s string, producer chan []byte) {
 	// Connect to the websocket backend (ARI)
 	var ariMessage string
 	url := strings.Join([]string{config.WebsocketURL, "?app=", s, "&api_key=", config.WSUser, ":", config.WSPassword}, "")
+	backoff := time.Second
+	arrivals := make(chan arrival, 256)
+	go dispatchEvents(arrivals, producer)
 
-	Info.Printf("Attempting to connect to ARI websocket at: %s", url)
-	ws, err := websocket.Dial(url, "ari", config.Origin)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Start the producer loop. Every message received from the websocket is
-	// passed to the PublishMessage() function.
-	Info.Printf("Starting producer loop for application %s", s)
-	for {
-		err = websocket.Message.Receive(ws, &ariMessage) // accept the message from the websocket
+	for retry := false; ; retry = true {
+		if retry {
+			websocketReconnects.WithLabelValues(s).Inc()
+		}
+		logger.Info("connecting to the ARI websocket", ari.LogApplication, s, "url", config.WebsocketURL)
+		ws, err := websocket.Dial(url, "ari", config.Origin)
 		if err != nil {
-			log.Fatal(err)
+			logger.Error("unable to connect the ARI websocket", ari.LogApplication, s, "retry_in", backoff.String(), ari.LogError, err)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > 30*time.Second {
+				backoff = 30 * time.Second
+			}
+			continue
 		}
-		go PublishMessage(ariMessage, producer) // publish message to the producer channel
+		backoff = time.Second
+		setConnected(s, true)
+
+		// Start the producer loop. Every message received from the websocket is
+		// passed to the PublishMessage() function, in order.
+		logger.Info("starting producer loop", ari.LogApplication, s)
+		for {
+			err = websocket.Message.Receive(ws, &ariMessage) // accept the message from the websocket
+			if err != nil {
+				logger.Error("lost the ARI websocket", ari.LogApplication, s, ari.LogError, err)
+				setConnected(s, false)
+				ws.Close()
+				break
+			}
+			recorder.record(s, ariMessage)
+			// the span covers the event from its arrival to its routing
+			ctx, span := tracer.Start(context.Background(), "receive event", trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attribute.String(ari.LogApplication, s)))
+			arrivals <- arrival{ctx: ctx, span: span, message: ariMessage}
+		}
+	}
+}
+
+// arrival is an ARI event as received from the websocket, along with the
+// span of its arrival.
+type arrival struct {
+	ctx     context.Context
+	span    trace.Span
+	message string
+}
+
+// dispatchEvents routes the events of an application one at a time, in the
+// order ARI sent them, to the queues of their dialogs, which publish them in
+// that order. It returns when arrivals is closed.
+func dispatchEvents(arrivals chan arrival, producer chan []byte) {
+	for a := range arrivals {
+		PublishMessage(a.ctx, a.message, producer) // publish message to the producer channel
+		a.span.End()
 	}
 }
 
-// PublishMessage takes an ARI event from the websocket and places it on the
-// producer channel.
-// Accepts two arguments:
+// PublishMessage takes an ARI event from the websocket and queues it for the
+// events topic of its dialog, creating the dialog on StasisStart.
+// Accepts three arguments:
+// * a context carrying the span of the event's arrival
 // * a string containing the ARI message
 // * a producer channel
-func PublishMessage(ariMessage string, producer chan []byte) {
+func PublishMessage(ctx context.Context, ariMessage string, producer chan []byte) {
 	// unmarshal into an ari.Event so we can append some extra information
 	var info eventInfo
 	var message ari.Event
@@ -127,10 +222,15 @@ func PublishMessage(ariMessage string, producer chan []byte) {
 	var exists bool = false
 	json.Unmarshal([]byte(ariMessage), &message)
 	json.Unmarshal([]byte(ariMessage), &info)
-	message.ServerID = config.ServerID
 	message.Timestamp = time.Now()
-	message.ARI_Body = ariMessage
-
+	message.ARI_Body = json.RawMessage(ariMessage)
+	eventsReceived.WithLabelValues(info.Type).Inc()
+	ctx, span := tracer.Start(ctx, "route event", trace.WithAttributes(attribute.String(ari.LogEventType, info.Type)))
+	defer span.End()
+
+	// objects are forgotten once the event is queued, so that the dialog
+	// publishes it before its topics are closed
+	var teardown func()
 	switch {
 	case info.Type == "StasisStart":
 
@@ -140,42 +240,40 @@ func PublishMessage(ariMessage string, producer chan []byte) {
 		if exists {
 			break
 		}
-		// since we're starting a new application instance, create the proxy side
-		dialogID := ari.UUID()
-		Info.Println("New StasisStart found. Created new dialogID of ", dialogID)
-		as, err := json.Marshal(ari.AppStart{Application: info.Application, DialogID: dialogID, ServerID: config.ServerID})
-		producer <- as
-
-		// TODO: this sleep is required to allow the application time to spin up. In the future we likely want
-		// to implement some sort of feedback mechanism in order to remove this sleep timer.
-		time.Sleep(50 * time.Millisecond)
-		if err != nil {
+		// refuse the channel when there are too many dialogs already
+		if !admitDialog(info.Application) {
+			logger.Warn("refusing channel: too many dialogs", ari.LogApplication, info.Application, ari.LogChannelID, info.Channel.ID)
+			go overload(info.Channel.ID)
+			eventsDropped.WithLabelValues(info.Type).Inc()
 			return
 		}
-
-		Info.Printf("Created new proxy instance mapping for dialog '%s' and channel '%s'", dialogID, info.Channel.ID)
-		pi = NewProxyInstance(dialogID)         // create new proxy instance for the dialog
-		proxyInstances.Add(info.Channel.ID, pi) // add the dialog to the proxyInstances map to track its life
+		// since we're starting a new application instance, create the proxy side
+		dialogID := ari.UUID()
+		logger.Info("created new dialog", ari.LogApplication, info.Application, ari.LogDialogID, dialogID, ari.LogChannelID, info.Channel.ID)
+		pi = NewProxyInstance(dialogID, info.Application) // create new proxy instance for the dialog
+		pi.addChannel(info.Channel.ID)                    // add the channel to the dialog and the proxyInstances map to track its life
 		exists = true
+		channelID := info.Channel.ID
+		pi.dispatch(func() { pi.announce(channelID, producer) })
 
 	case info.Type == "StasisEnd":
-		Info.Printf("Ending application instance for channel '%s'", info.Channel.ID)
+		logger.Info("ending application instance", ari.LogApplication, info.Application, ari.LogChannelID, info.Channel.ID)
 		// on application end, perform clean up checks
 		pi, exists = proxyInstances.Get(info.Channel.ID)
 		if exists {
-			pi.removeAllObjects()
+			teardown = func() { pi.removeAllObjects("stasis_end") }
 		}
 
 	case info.Type == "BridgeDestroyed":
 		pi, exists = proxyInstances.Get(info.Bridge.ID)
 		if exists {
-			pi.removeObject(info.Bridge.ID)
+			teardown = func() { pi.removeObject(info.Bridge.ID) }
 		}
 
 	case info.Type == "ChannelDestroyed":
 		pi, exists = proxyInstances.Get(info.Channel.ID)
 		if exists {
-			pi.removeObject(info.Channel.ID)
+			teardown = func() { pi.removeObject(info.Channel.ID) }
 		}
 
 	// check if prefix is part of the minChan{} struct
@@ -195,23 +293,147 @@ func PublishMessage(ariMessage string, producer chan []byte) {
 		pi, exists = proxyInstances.Get(info.Recording.Name)
 
 	default:
-		Warning.Println("No handler for event type")
+		logger.Warn("no handler for event type", ari.LogApplication, info.Application, ari.LogEventType, info.Type)
 		//pi, exists = proxyInstances[]
 		// if not matching, then we need to perform checks against the
 		// existing map to determine where to send this ARI message.
 	}
 
-	// marshal the message back into a string
-	busMessage, err := json.Marshal(message)
+	// queue the message for the dialog's events topic, behind the events
+	// of the dialog that are still being published
+	if exists && pi.dispatch(func() {
+		pi.publishEvent(ctx, message)
+		pi.auditEvent(info.Type, info.Channel.ID)
+		eventsRouted.WithLabelValues(info.Type).Inc()
+	}) {
+		span.SetAttributes(attribute.String(ari.LogDialogID, pi.dialogID))
+	} else {
+		span.SetAttributes(attribute.Bool("dropped", true))
+		eventsDropped.WithLabelValues(info.Type).Inc()
+	}
+	if teardown != nil {
+		teardown()
+	}
+}
+
+// announce publishes the AppStart of a new dialog on the signalling topic of
+// its application and to the taps, before the dialog publishes any event.
+func (p *proxyInstance) announce(channelID string, producer chan []byte) {
+	appStart := ari.AppStart{Application: p.application, DialogID: p.dialogID, ServerID: config.ServerID}
+	as, err := ari.Marshal(appStart)
+	if err != nil {
+		p.log().Error("unable to marshal AppStart", ari.LogError, err)
+		return
+	}
+	producer <- as
+	appStarts.WithLabelValues(p.application).Inc()
+	audit.record(auditRecord{Time: time.Now(), Type: "app_start", DialogID: p.dialogID, Application: p.application, ChannelID: channelID})
+	for _, t := range taps {
+		t.publishAppStart(appStart)
+	}
+
+	// TODO: this sleep is required to allow the application time to spin up. In the future we likely want
+	// to implement some sort of feedback mechanism in order to remove this sleep timer. It only holds up
+	// the events of this dialog.
+	time.Sleep(50 * time.Millisecond)
+}
+
+// dispatch queues work on the events of the dialog, such as publishing one,
+// to run once the work queued before it is done. It reports false, dropping
+// the work, once the dialog has been torn down.
+func (p *proxyInstance) dispatch(work func()) bool {
+	p.queueLock.Lock()
+	defer p.queueLock.Unlock()
+	if p.queueClosed {
+		return false
+	}
+	p.queue = append(p.queue, work)
+	select {
+	case p.queueReady <- true:
+	default:
+	}
+	return true
+}
+
+// runQueue runs the work dispatched to the dialog, in order. The queue is
+// not bounded, so that a dialog whose events cannot be published holds up
+// neither ARI's websocket nor the other dialogs. Once the dialog is torn
+// down and the queue is empty, runQueue closes the dialog's topics.
+func (p *proxyInstance) runQueue() {
+	for {
+		p.queueLock.Lock()
+		work, closed := p.queue, p.queueClosed
+		p.queue = nil
+		p.queueLock.Unlock()
+		for _, w := range work {
+			w()
+		}
+		if len(work) > 0 {
+			continue
+		}
+		if closed {
+			ari.CloseDialogTopics(p.dialogID)
+			return
+		}
+		<-p.queueReady
+	}
+}
+
+// publishEvent stamps an Event with the dialog's envelope and publishes it on
+// the events topic and to the taps. The envelope carries the trace context
+// of the publication, for the application's spans to join.
+func (p *proxyInstance) publishEvent(ctx context.Context, message ari.Event) {
+	ctx, span := tracer.Start(ctx, "publish event", trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+	message.Envelope = p.envelope()
+	message.Trace = traceContext(ctx)
+
+	// only the dialog's queue publishes events, so they reach the bus in the
+	// order they are numbered. The lock guards the number for the admin API
+	// and is not held while sending, which blocks while the bus stalls.
+	p.eventLock.Lock()
+	p.eventSequence++
+	message.Sequence = p.eventSequence
+	p.eventLock.Unlock()
+	// marshal the message for the bus, in the envelope version the
+	// applications understand
+	busEvent := message
+	if config.EventVersion != 0 {
+		busEvent = message.AsVersion(config.EventVersion)
+	}
+	err := busEvent.Compress()
+	if err == nil {
+		err = busEvent.Encrypt(appKeys[p.application])
+	}
+	if err != nil {
+		p.log().Error("unable to prepare event", ari.LogEventType, message.Type, ari.LogError, err)
+		return
+	}
+	busMessage, err := ari.Marshal(&busEvent)
 	if err != nil {
-		Error.Println(err)
+		p.log().Error("unable to marshal event", ari.LogEventType, message.Type, ari.LogError, err)
 		return
 	}
-	Debug.Printf("Bus Data:\n%s\n", busMessage)
+	p.log().Debug("publishing event", ari.LogEventType, message.Type, "sequence", message.Sequence, "body", string(message.ARI_Body))
+	p.Events <- busMessage
 
-	// push the busMessage onto the producer channel
-	if exists {
-		pi.Events <- busMessage
+	if config.Admin.Listen != "" {
+		p.recordEvent(message)
+	}
+
+	for _, t := range taps {
+		t.publishEvent(p.dialogID, &message)
+	}
+}
+
+// envelope returns the envelope of the messages the proxy instance sends.
+func (p *proxyInstance) envelope() ari.Envelope {
+	return ari.Envelope{
+		Version:         ari.EnvelopeVersion,
+		DialogID:        p.dialogID,
+		Application:     p.application,
+		ServerID:        config.ServerID,
+		ProxyInstanceID: proxyInstanceID,
 	}
 }
 
@@ -234,6 +456,35 @@ func (p *proxyInstanceMap) Get(id string) (*proxyInstance, bool) {
 	return pi, true
 }
 
+// GetDialog returns the proxy instance of a dialog from the global map of
+// active proxy instances.
+func (p *proxyInstanceMap) GetDialog(dialogID string) (*proxyInstance, bool) {
+	p.mapLock.RLock()
+	defer p.mapLock.RUnlock()
+	for _, pi := range p.instanceMap {
+		if pi.dialogID == dialogID {
+			return pi, true
+		}
+	}
+	return nil, false
+}
+
+// Dialogs returns every proxy instance in the global map of active proxy
+// instances once, however many ARI objects map to it.
+func (p *proxyInstanceMap) Dialogs() []*proxyInstance {
+	p.mapLock.RLock()
+	defer p.mapLock.RUnlock()
+	seen := make(map[*proxyInstance]bool)
+	var dialogs []*proxyInstance
+	for _, pi := range p.instanceMap {
+		if !seen[pi] {
+			seen[pi] = true
+			dialogs = append(dialogs, pi)
+		}
+	}
+	return dialogs
+}
+
 // Remove deletes an entry in the global proxyInstanceMap
 func (p *proxyInstanceMap) Remove(id string) {
 	p.mapLock.Lock()
@@ -242,20 +493,31 @@ func (p *proxyInstanceMap) Remove(id string) {
 }
 
 // shutDown closes the quit channel to signal all of a ProxyInstance's goroutines
-// to return
-func (p *proxyInstance) shutDown() {
-	select {
-	case _, ok := (<-p.quit):
-		if !ok {
-			return
-		}
-	default:
+// to return, releases the dialog's topics on the message bus and records why
+// the dialog ended in the audit trail. Only the first call tears the dialog
+// down, as the last object of a dialog may go away at the same time as it is
+// terminated.
+func (p *proxyInstance) shutDown(reason string) {
+	p.shutDownOnce.Do(func() {
 		close(p.quit)
-	}
+		releaseDialog(p.application)
+		p.auditTeardown(reason)
+		// the queue closes the topics once the events queued before are
+		// published
+		p.queueLock.Lock()
+		p.queueClosed = true
+		p.queueLock.Unlock()
+		select {
+		case p.queueReady <- true:
+		default:
+		}
+	})
 }
 
 // addObject adds an object reference to the proxyInstance mapping
 func (p *proxyInstance) addObject(id string) {
+	p.objectLock.Lock()
+	defer p.objectLock.Unlock()
 	for i := range p.ariObjects {
 		if p.ariObjects[i] == id {
 			//object already is associated with this proxyInstance
@@ -267,32 +529,97 @@ func (p *proxyInstance) addObject(id string) {
 
 }
 
+// addChannel adds a channel to the proxyInstance mapping, remembering that
+// terminate has to hang it up.
+func (p *proxyInstance) addChannel(id string) {
+	p.addObject(id)
+	p.objectLock.Lock()
+	p.channelObjects[id] = true
+	p.objectLock.Unlock()
+}
+
 // removeObject removes an object reference from the proxyInstance mapping
 func (p *proxyInstance) removeObject(id string) {
 	// remove an object from the map.
+	p.objectLock.Lock()
 	for i := range p.ariObjects {
 		if p.ariObjects[i] == id {
 			// rewrite the p.ariObjects string slice to append all values up to
 			// the index value of 'i', and all values of 'i'+1 and later.
 			p.ariObjects = append(p.ariObjects[:i], p.ariObjects[i+1:]...)
+			break
 		}
 	}
+	delete(p.channelObjects, id)
+	remaining := len(p.ariObjects)
+	p.objectLock.Unlock()
 	// remove the instance from our tracking map
 	proxyInstances.Remove(id)
 
 	// if there are no more objects, shut'rdown
-	if len(p.ariObjects) == 0 {
-		p.shutDown()
+	if remaining == 0 {
+		p.shutDown("objects_destroyed")
 	}
 }
 
-// removeAllObjects will remove all object references from the proxyInstance mapping
-func (p *proxyInstance) removeAllObjects() {
+// removeAllObjects will remove all object references from the proxyInstance
+// mapping, ending the dialog for the given reason.
+func (p *proxyInstance) removeAllObjects(reason string) {
 	// remove all objects from the map as our application is shutting down.
-	for _, obj := range p.ariObjects {
+	for _, obj := range p.objects() {
 		proxyInstances.Remove(obj)
 	}
-	p.shutDown() // destroy the application / proxy instance
+	p.shutDown(reason) // destroy the application / proxy instance
+}
+
+// objects returns a copy of the IDs of the ARI objects the proxy instance
+// owns.
+func (p *proxyInstance) objects() []string {
+	p.objectLock.RLock()
+	defer p.objectLock.RUnlock()
+	objects := make([]string, len(p.ariObjects))
+	copy(objects, p.ariObjects)
+	return objects
+}
+
+// owns reports whether an ARI object belongs to the proxy instance.
+func (p *proxyInstance) owns(id string) bool {
+	p.objectLock.RLock()
+	defer p.objectLock.RUnlock()
+	for _, obj := range p.ariObjects {
+		if obj == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedChannels returns the IDs of the channels the proxy instance owns.
+func (p *proxyInstance) ownedChannels() []string {
+	p.objectLock.RLock()
+	defer p.objectLock.RUnlock()
+	var channels []string
+	for _, obj := range p.ariObjects {
+		if p.channelObjects[obj] {
+			channels = append(channels, obj)
+		}
+	}
+	return channels
+}
+
+// terminate hangs up the channels owned by the proxy instance and tears the
+// instance down. Bridges, playbacks and recordings are not deleted: they end
+// with the channels or are left to ARI to clean up.
+func (p *proxyInstance) terminate() {
+	for _, obj := range p.ownedChannels() {
+		res, err := ariRequest("DELETE", strings.Join([]string{"/channels/", obj}, ""), "")
+		if err != nil {
+			p.log().Error("unable to hang up channel", ari.LogChannelID, obj, ari.LogError, err)
+			continue
+		}
+		res.Body.Close()
+	}
+	p.removeAllObjects("terminated")
 }
 
 // runCommandConsumer starts the consumer for accepting Commands from
@@ -300,8 +627,7 @@ func (p *proxyInstance) removeAllObjects() {
 func (p *proxyInstance) runCommandConsumer(dialogID string) {
 	commandTopic := strings.Join([]string{"commands", dialogID}, "_")
 	responseTopic := strings.Join([]string{"responses", dialogID}, "_")
-	Debug.Println("Topics are:", commandTopic, " ", responseTopic)
-	p.responseChannel = ari.InitProducer(responseTopic)
+	p.log().Debug("starting command consumer", "command_topic", commandTopic, "response_topic", responseTopic)
 
 	// waits for the TopicExists function to return a channel
 	select {
@@ -309,49 +635,126 @@ func (p *proxyInstance) runCommandConsumer(dialogID string) {
 		p.commandChannel = ari.InitConsumer(commandTopic)
 	case <-time.After(10 * time.Second):
 		// if the application instance hasn't come up after a period of time, gracefully end the proxy instance
-		p.removeAllObjects()
+		p.removeAllObjects("application_timeout")
 		return
 	}
 
+	// commands are checked and admitted in the order they arrive, before
+	// they are processed concurrently, so that commands waiting for a limit
+	// wait here rather than in a goroutine each
+	check := ari.NewSequenceCheck(dialogID, commandTopic)
 	for {
 		select {
-		case jsonCommand := <-p.commandChannel:
-			go p.processCommand(jsonCommand, p.responseChannel)
+		case message := <-p.commandChannel:
+			var c ari.Command
+			if err := ari.Unmarshal(message, &c); err != nil {
+				p.log().Debug("dropping undecodable command", ari.LogTopic, commandTopic, ari.LogError, err)
+				continue
+			}
+			// verify first, so that forged commands cannot disturb the sequence
+			if err := c.Verify(appKeys[p.application]); err != nil {
+				p.log().Warn("dropping command", ari.LogUniqueID, c.UniqueID, ari.LogError, err)
+				continue
+			}
+			if !check.Accept(c.Envelope) {
+				continue
+			}
+			// the span joins the trace of the application that sent the command
+			ctx := otel.GetTextMapPropagator().Extract(context.Background(), c.Trace)
+			ctx, span := tracer.Start(ctx, "receive command", trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attribute.String(ari.LogApplication, p.application), attribute.String(ari.LogDialogID, p.dialogID)))
+			a := p.admitCommand(c)
+			go func(c ari.Command) {
+				defer span.End()
+				p.processCommand(ctx, c, p.responseChannel, a)
+			}(c)
 		case <-p.quit:
 			return
 		}
 	}
 }
 
-// processCommand processes commands from applications and submits them to the
-// REST interface.
-func (p *proxyInstance) processCommand(jsonCommand []byte, responseProducer chan []byte) {
-	var c ari.Command
+// admission is the outcome of checking a command against the policy and the
+// limits: the status and reason it is refused with, or the function
+// releasing the limits it holds.
+type admission struct {
+	status  int
+	err     error
+	release func()
+}
+
+// admitCommand checks a command against the policy of the dialog's
+// application and passes it through the limits, waiting when they queue.
+func (p *proxyInstance) admitCommand(c ari.Command) admission {
+	if err := p.authorize(c); err != nil {
+		return admission{status: http.StatusForbidden, err: err}
+	}
+	release, err := p.admit()
+	if err != nil {
+		return admission{status: http.StatusTooManyRequests, err: err}
+	}
+	return admission{release: release}
+}
+
+// processCommand processes commands from applications, admitted by
+// admitCommand, and submits them to the REST interface.
+func (p *proxyInstance) processCommand(ctx context.Context, c ari.Command, responseProducer chan []byte, a admission) {
 	var r ari.CommandResponse
 	i := ID{ID: "", Name: ""}
-	Debug.Printf("jsonCommand is %s\n", string(jsonCommand))
-	json.Unmarshal(jsonCommand, &c)
+	start := time.Now()
+	p.log().Debug("processing command", ari.LogUniqueID, c.UniqueID, "method", c.Method, "url", c.URL)
+	ctx, span := tracer.Start(ctx, "process command", trace.WithAttributes(attribute.String(ari.LogUniqueID, c.UniqueID),
+		attribute.String("http.request.method", c.Method), attribute.String("url.path", c.URL)))
+	defer func() {
+		commandsProcessed.WithLabelValues(methodLabel(c.Method), strconv.Itoa(r.StatusCode)).Inc()
+		p.auditCommand(c, r.StatusCode, start)
+		span.SetAttributes(attribute.Int("http.response.status_code", r.StatusCode))
+		if r.StatusCode >= 400 {
+			span.SetStatus(codes.Error, http.StatusText(r.StatusCode))
+		}
+		span.End()
+	}()
+	if a.err != nil {
+		if a.status == http.StatusForbidden {
+			p.log().Warn("refusing command", ari.LogUniqueID, c.UniqueID, ari.LogError, a.err)
+		} else {
+			p.log().Warn("throttling command", ari.LogUniqueID, c.UniqueID, ari.LogError, a.err)
+		}
+		r = ari.CommandResponse{UniqueID: c.UniqueID, StatusCode: a.status, ResponseBody: a.err.Error()}
+		p.sendResponse(ctx, responseProducer, r)
+		return
+	}
+	defer a.release()
 
 	//TODO:  Try to come up with something that makes me feel less dirty
 	if c.Method == "POST" && strings.Contains(c.URL, "/channels/") && strings.Count(c.URL, "/") == 2 {
 		chanID := strings.TrimPrefix(c.URL, "/channels/")
 		if chanID != "" {
-			p.addObject(chanID)
+			p.addChannel(chanID)
 		}
 	}
 	//ENDTODO
 
-	fullURL := strings.Join([]string{config.StasisURL, c.URL, "?api_key=", config.WSUser, ":", config.WSPassword}, "")
-
-	Debug.Printf("fullURL is %s\n", fullURL)
-	req, err := http.NewRequest(c.Method, fullURL, bytes.NewBufferString(c.Body))
-	req.Header.Set("Content-Type", "application/json")
-	res, err := client.Do(req)
+	ariCtx, ariSpan := tracer.Start(ctx, strings.Join([]string{"ARI", c.Method}, " "), trace.WithSpanKind(trace.SpanKindClient))
+	res, err := ariRequestContext(ariCtx, c.Method, c.URL, c.Body)
+	if err != nil {
+		ariSpan.RecordError(err)
+	}
+	ariSpan.End()
+	if err != nil {
+		p.log().Error("unable to send command to ARI", ari.LogUniqueID, c.UniqueID, ari.LogError, err)
+		r = ari.CommandResponse{UniqueID: c.UniqueID, StatusCode: http.StatusBadGateway, ResponseBody: err.Error()}
+		p.sendResponse(ctx, responseProducer, r)
+		return
+	}
+	defer res.Body.Close()
 	buf := new(bytes.Buffer)
 	buf.ReadFrom(res.Body)
-	Debug.Printf("Response body is %s\n", buf.String())
+	p.log().Debug("received ARI response", ari.LogUniqueID, c.UniqueID, "status", res.StatusCode, "body", buf.String())
 	json.Unmarshal(buf.Bytes(), &i)
-	if i.ID != "" {
+	if i.ID != "" && i.Dialplan != nil {
+		p.addChannel(i.ID)
+	} else if i.ID != "" {
 		p.addObject(i.ID)
 	} else if i.Name != "" {
 		p.addObject(i.Name)
@@ -359,12 +762,58 @@ func (p *proxyInstance) processCommand(jsonCommand []byte, responseProducer chan
 	r.ResponseBody = buf.String()
 	r.StatusCode = res.StatusCode
 	r.UniqueID = c.UniqueID // return the Command UID in the response
-	sendJSON, err := json.Marshal(r)
+	p.sendResponse(ctx, responseProducer, r)
+}
+
+// sendResponse stamps a CommandResponse with the dialog's envelope, marshals
+// it and places it on the response producer channel. Only responses sent on
+// the bus are numbered and compressed, as the taps hand theirs directly to
+// clients that do not use the library.
+func (p *proxyInstance) sendResponse(ctx context.Context, responseProducer chan []byte, r ari.CommandResponse) {
+	ctx, span := tracer.Start(ctx, "publish response", trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+	r.Envelope = p.envelope()
+	r.Trace = traceContext(ctx)
+	if responseProducer == p.responseChannel {
+		p.responseLock.Lock()
+		defer p.responseLock.Unlock()
+		r.Sequence = atomic.AddUint64(&p.responseSequence, 1)
+		if err := r.Compress(); err != nil {
+			p.log().Error("unable to compress response", ari.LogUniqueID, r.UniqueID, ari.LogError, err)
+		}
+	}
+	message, err := ari.Marshal(r)
 	if err != nil {
-		Error.Println(err)
+		p.log().Error("unable to marshal response", ari.LogUniqueID, r.UniqueID, ari.LogError, err)
 	}
-	Debug.Printf("sendJSON is %s\n", string(sendJSON))
-	responseProducer <- sendJSON
+	p.log().Debug("sending response", ari.LogUniqueID, r.UniqueID, "status", r.StatusCode)
+	responseProducer <- message
+}
+
+// ariRequest sends a request to the ARI REST interface. The url is relative
+// to the configured Stasis URL and may carry a query string.
+func ariRequest(method string, url string, body string) (*http.Response, error) {
+	return ariRequestContext(context.Background(), method, url, body)
+}
+
+// ariRequestContext sends a request to the ARI REST interface, which is
+// cancelled along with the context.
+func ariRequestContext(ctx context.Context, method string, url string, body string) (*http.Response, error) {
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+	fullURL := strings.Join([]string{config.StasisURL, url, separator, "api_key=", config.WSUser, ":", config.WSPassword}, "")
+	logger.Debug("sending ARI request", "method", method, "url", url)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	res, err := client.Do(req)
+	ariRequestDuration.WithLabelValues(methodLabel(method)).Observe(time.Since(start).Seconds())
+	return res, err
 }
 
 // vim: tabstop=4 softtabstop=4 shiftwidth=4 noexpandtab tw=72
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"github.com/nvisibleinc/go-ari-proxy/aritest"
+	"google.golang.org/grpc/test/bufconn"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	fake      *aritest.Server                          // fake ARI the proxy of the tests is connected to
+	instances = make(map[string]chan *ari.AppInstance) // dialogs of the applications as they start
+	grpcTap   *grpcServer                              // gRPC API of the proxy of the tests
+	grpcLis   *bufconn.Listener                        // in-process listener of the gRPC API
+)
+
+// timeout bounds every wait of the tests.
+const timeout = 5 * time.Second
+
+// TestMain connects a proxy to the fake ARI over the in-process bus, and
+// starts an application for each test on the other side of the bus. The
+// gRPC API is served on an in-process listener.
+func TestMain(m *testing.M) {
+	fake = aritest.NewServer()
+	fake.APIKey = "proxy:secret"
+	config = Config{
+		ServerID:     "test",
+		Applications: []string{"inbound", "bridged", "recorded", "reconnect", "grpc", "ordered", "replayed"},
+		WebsocketURL: fake.WebsocketURL,
+		StasisURL:    fake.URL,
+		WSUser:       "proxy",
+		WSPassword:   "secret",
+		Origin:       "http://localhost/",
+		MessageBus:   "MEMORY",
+		GRPC: grpcConfig{
+			Tokens:      map[string][]string{"token": {"grpc"}},
+			AdminTokens: []string{"admin"},
+		},
+	}
+	logLevel.Set(slog.LevelWarn)
+	// compress every message on the bus, which the library undoes for the
+	// applications and which must not reach the gRPC clients
+	ari.SetCompression("gzip", 1)
+	if err := ari.InitBus(config.MessageBus, nil); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	grpcTap = newGRPCServer()
+	grpcLis = bufconn.Listen(1 << 20)
+	taps = append(taps, grpcTap)
+	go grpcTap.serve(grpcLis)
+	for _, app := range config.Applications {
+		go runEventHandler(app, ari.InitProducer(app))
+		instances[app] = startApp(app)
+	}
+	if err := waitConnected(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	fake.Close()
+	os.Exit(code)
+}
+
+// waitConnected waits for the proxy to connect the websockets of all the
+// applications.
+func waitConnected() error {
+	for _, app := range config.Applications {
+		if err := fake.WaitConnected(app, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startApp starts an application on the bus and returns the instances of
+// its dialogs as they start.
+func startApp(name string) chan *ari.AppInstance {
+	started := make(chan *ari.AppInstance, 1)
+	ari.NewApp().Init(name, func(ai *ari.AppInstance) { started <- ai })
+	return started
+}
+
+// waitInstance waits for the next dialog of an application.
+func waitInstance(t *testing.T, app string) *ari.AppInstance {
+	t.Helper()
+	select {
+	case ai := <-instances[app]:
+		return ai
+	case <-time.After(timeout):
+		t.Fatal("no AppStart received")
+		return nil
+	}
+}
+
+// waitEvent waits for an event of the given type on a dialog, skipping the
+// others, and returns what the proxy extracted from it for routing.
+func waitEvent(t *testing.T, ai *ari.AppInstance, eventType string) eventInfo {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case e := <-ai.Events:
+			if e.Type != eventType {
+				continue
+			}
+			var info eventInfo
+			if err := json.Unmarshal(e.ARI_Body, &info); err != nil {
+				t.Fatalf("%s: %s", eventType, err)
+			}
+			return info
+		case <-deadline:
+			t.Fatalf("no %s event received", eventType)
+		}
+	}
+}
+
+// expectDigits waits for the ChannelDtmfReceived events of the digits on a
+// dialog, skipping the other events. The application drops events that
+// arrive after a later one, so every digit only arrives when the proxy keeps
+// the order of the events.
+func expectDigits(t *testing.T, ai *ari.AppInstance, digits string) {
+	t.Helper()
+	var sequence uint64
+	for _, digit := range digits {
+		deadline := time.After(timeout)
+		var e *ari.Event
+		for e == nil || e.Type != "ChannelDtmfReceived" {
+			select {
+			case e = <-ai.Events:
+			case <-deadline:
+				t.Fatalf("no event for digit %c", digit)
+			}
+		}
+		var dtmf struct {
+			Digit string `json:"digit"`
+		}
+		json.Unmarshal(e.ARI_Body, &dtmf)
+		if dtmf.Digit != string(digit) || e.Sequence <= sequence {
+			t.Fatalf("received digit %s with sequence %d after sequence %d, want digit %c", dtmf.Digit, e.Sequence, sequence, digit)
+		}
+		sequence = e.Sequence
+	}
+}
+
+// waitEnded waits for the proxy to end the dialog an ARI object belongs to.
+func waitEnded(t *testing.T, id string) {
+	t.Helper()
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, ok := proxyInstances.Get(id); !ok {
+			return
+		}
+	}
+	t.Fatalf("the dialog of %s did not end", id)
+}
+
+func TestInboundCall(t *testing.T) {
+	id := fake.Call("inbound")
+	ai := waitInstance(t, "inbound")
+	if info := waitEvent(t, ai, "StasisStart"); info.Channel.ID != id {
+		t.Fatalf("StasisStart is about channel %q, want %q", info.Channel.ID, id)
+	}
+
+	if err := ai.ChannelsAnswer(id); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ai, "ChannelStateChange")
+	if c, _ := fake.Channel(id); c.State != "Up" {
+		t.Errorf("channel state is %q after answering, want Up", c.State)
+	}
+	if err := ai.ChannelsAnswer("missing"); err == nil {
+		t.Error("answering a missing channel succeeded")
+	}
+
+	p, err := ai.ChannelsPlay(id, "sound:hello-world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.State != "queued" {
+		t.Errorf("playback state is %q, want queued", p.State)
+	}
+	if info := waitEvent(t, ai, "PlaybackStarted"); info.Playback.ID != p.Id {
+		t.Errorf("PlaybackStarted is about playback %q, want %q", info.Playback.ID, p.Id)
+	}
+	waitEvent(t, ai, "PlaybackFinished")
+
+	fake.Hangup(id)
+	waitEnded(t, id)
+	if _, ok := fake.Channel(id); ok {
+		t.Error("channel still exists after hanging up")
+	}
+}
+
+func TestOriginateAndBridge(t *testing.T) {
+	in := fake.Call("bridged")
+	ai := waitInstance(t, "bridged")
+	waitEvent(t, ai, "StasisStart")
+
+	// the originated channel joins the dialog as soon as the proxy has its ID
+	out, err := ai.ChannelsOriginate("PJSIP/bob", "", "", "", "bridged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := waitEvent(t, ai, "StasisStart"); info.Channel.ID != out.Id {
+		t.Fatalf("StasisStart is about channel %q, want %q", info.Channel.ID, out.Id)
+	}
+
+	b, err := ai.BridgesCreate("mixing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ai.BridgesAddChannel(b.Id, in+","+out.Id); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ai, "ChannelEnteredBridge")
+	waitEvent(t, ai, "ChannelEnteredBridge")
+	if bridge, _ := fake.Bridge(b.Id); len(bridge.Channels) != 2 {
+		t.Errorf("bridge has channels %v, want 2", bridge.Channels)
+	}
+
+	if err := ai.BridgesDestroy(b.Id); err != nil {
+		t.Fatal(err)
+	}
+	if info := waitEvent(t, ai, "BridgeDestroyed"); info.Bridge.ID != b.Id {
+		t.Errorf("BridgeDestroyed is about bridge %q, want %q", info.Bridge.ID, b.Id)
+	}
+	if pi, ok := proxyInstances.Get(in); !ok || pi.owns(b.Id) {
+		t.Error("the dialog still owns the destroyed bridge")
+	}
+
+	if err := ai.ChannelsHangup(out.Id); err != nil {
+		t.Fatal(err)
+	}
+	waitEnded(t, out.Id)
+	fake.Hangup(in)
+	waitEnded(t, in)
+}
+
+func TestRecording(t *testing.T) {
+	id := fake.Call("recorded")
+	ai := waitInstance(t, "recorded")
+	waitEvent(t, ai, "StasisStart")
+
+	name := ari.UUID()
+	r, err := ai.ChannelsRecord(id, name, "wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != name || r.State != "queued" {
+		t.Errorf("recording is %q in state %q, want %q queued", r.Name, r.State, name)
+	}
+	waitEvent(t, ai, "RecordingStarted")
+	if _, err := ai.ChannelsRecord(id, name, "wav"); err == nil {
+		t.Error("a second recording with the same name succeeded")
+	}
+	if err := ai.RecordingsStop(name); err != nil {
+		t.Fatal(err)
+	}
+	if info := waitEvent(t, ai, "RecordingFinished"); info.Recording.Name != name {
+		t.Errorf("RecordingFinished is about recording %q, want %q", info.Recording.Name, name)
+	}
+	if rec, _ := fake.Recording(name); rec.State != "done" {
+		t.Errorf("recording state is %q, want done", rec.State)
+	}
+
+	fake.Hangup(id)
+	waitEnded(t, id)
+}
+
+func TestEventOrder(t *testing.T) {
+	id := fake.Call("ordered")
+	ai := waitInstance(t, "ordered")
+	waitEvent(t, ai, "StasisStart")
+
+	const digits = "0123456789*#ABCD"
+	for _, digit := range digits {
+		fake.DTMF(id, string(digit))
+	}
+	expectDigits(t, ai, digits)
+
+	fake.Hangup(id)
+	waitEnded(t, id)
+}
+
+func TestShutDownOnce(t *testing.T) {
+	if !admitDialog("teardown") {
+		t.Fatal("the dialog was not admitted")
+	}
+	pi := NewProxyInstance(ari.UUID(), "teardown")
+	// the last object may go away while the dialog is being terminated
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pi.shutDown("terminated")
+		}()
+	}
+	wg.Wait()
+	dialogCounts.Lock()
+	active := dialogCounts.apps["teardown"]
+	dialogCounts.Unlock()
+	if active != 0 {
+		t.Errorf("%d dialogs are counted after the teardown, want 0", active)
+	}
+}
+
+func TestDialogQueues(t *testing.T) {
+	for _, app := range []string{"stalled", "flowing"} {
+		if !admitDialog(app) {
+			t.Fatal("the dialog was not admitted")
+		}
+	}
+	stalled := NewProxyInstance(ari.UUID(), "stalled")
+	flowing := NewProxyInstance(ari.UUID(), "flowing")
+	defer flowing.shutDown("terminated")
+
+	// a dialog whose events cannot be published holds up no other
+	unblock := make(chan bool)
+	stalled.dispatch(func() { <-unblock })
+	var order []int
+	done := make(chan bool)
+	for i := 0; i < 100; i++ {
+		i := i
+		flowing.dispatch(func() { order = append(order, i) })
+	}
+	flowing.dispatch(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("the events of a dialog waited for another dialog")
+	}
+	for i, n := range order {
+		if n != i {
+			t.Fatalf("work ran in the order %v", order)
+		}
+	}
+
+	// work queued before the teardown still runs, work queued after is dropped
+	ran := make(chan bool, 1)
+	stalled.dispatch(func() { ran <- true })
+	stalled.shutDown("terminated")
+	if stalled.dispatch(func() { t.Error("work ran after the teardown") }) {
+		t.Error("work was queued after the teardown")
+	}
+	close(unblock)
+	select {
+	case <-ran:
+	case <-time.After(timeout):
+		t.Fatal("work queued before the teardown did not run")
+	}
+}
+
+func TestShowDialogStalledBus(t *testing.T) {
+	if !admitDialog("inspected") {
+		t.Fatal("the dialog was not admitted")
+	}
+	pi := NewProxyInstance(ari.UUID(), "inspected")
+	defer pi.shutDown("terminated")
+	stalled := make(chan []byte)
+	pi.Events = stalled
+	pi.dispatch(func() { pi.publishEvent(context.Background(), ari.Event{Type: "ChannelDtmfReceived"}) })
+	defer func() { <-stalled }()
+
+	// the admin API shows the dialog while its event waits for the bus
+	deadline := time.Now().Add(timeout)
+	for {
+		shown := make(chan dialogDetail, 1)
+		go func() {
+			w := httptest.NewRecorder()
+			showDialog(w, pi)
+			var d dialogDetail
+			json.Unmarshal(w.Body.Bytes(), &d)
+			shown <- d
+		}()
+		select {
+		case d := <-shown:
+			if d.EventSequence == 1 {
+				return
+			}
+		case <-time.After(timeout):
+			t.Fatal("the admin API waited for the stalled bus")
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the event was not numbered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	fake.Disconnect()
+	if err := waitConnected(); err != nil {
+		t.Fatal(err)
+	}
+
+	id := fake.Call("reconnect")
+	ai := waitInstance(t, "reconnect")
+	if info := waitEvent(t, ai, "StasisStart"); info.Channel.ID != id {
+		t.Fatalf("StasisStart is about channel %q, want %q", info.Channel.ID, id)
+	}
+	fake.Hangup(id)
+	waitEnded(t, id)
+}
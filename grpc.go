@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/nvisibleinc/go-ari-library"
+	"github.com/nvisibleinc/go-ari-proxy/aripb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net"
+	"strings"
+	"sync"
+)
+
+// grpcServer implements the AriProxy gRPC service. It fans AppStarts and
+// dialog events out to the attached streams and is an eventTap.
+type grpcServer struct {
+	aripb.UnimplementedAriProxyServer
+	lock          sync.RWMutex
+	appStreams    map[string]map[chan *aripb.AppStart]bool // application to subscribed streams
+	dialogStreams map[string]map[chan *aripb.Event]bool    // dialog ID to attached streams
+}
+
+// newGRPCServer initializes a gRPC server without any streams.
+func newGRPCServer() *grpcServer {
+	return &grpcServer{
+		appStreams:    make(map[string]map[chan *aripb.AppStart]bool),
+		dialogStreams: make(map[string]map[chan *aripb.Event]bool),
+	}
+}
+
+// runGRPCServer serves the gRPC API on the configured address until the
+// listener fails.
+func runGRPCServer(g *grpcServer) {
+	lis, err := net.Listen("tcp", config.GRPC.Listen)
+	if err != nil {
+		fatal("unable to listen for the gRPC API", ari.LogError, err)
+	}
+	logger.Info("starting gRPC API", "listen", config.GRPC.Listen)
+	err = g.serve(lis)
+	fatal("unable to serve the gRPC API", ari.LogError, err)
+}
+
+// serve serves the gRPC API on a listener, which may be an in-process
+// listener for clients in the same binary.
+func (g *grpcServer) serve(lis net.Listener) error {
+	s := grpc.NewServer()
+	aripb.RegisterAriProxyServer(s, g)
+	return s.Serve(lis)
+}
+
+// SubscribeAppStarts streams the AppStarts of an application until the
+// client goes away.
+func (g *grpcServer) SubscribeAppStarts(req *aripb.SubscribeAppStartsRequest, stream aripb.AriProxy_SubscribeAppStartsServer) error {
+	if !allowed(stream.Context(), req.Application) {
+		return status.Error(codes.PermissionDenied, "application not allowed")
+	}
+	c := make(chan *aripb.AppStart, 64)
+	g.lock.Lock()
+	if g.appStreams[req.Application] == nil {
+		g.appStreams[req.Application] = make(map[chan *aripb.AppStart]bool)
+	}
+	g.appStreams[req.Application][c] = true
+	g.lock.Unlock()
+	defer func() {
+		g.lock.Lock()
+		delete(g.appStreams[req.Application], c)
+		g.lock.Unlock()
+	}()
+
+	for {
+		select {
+		case as := <-c:
+			if err := stream.Send(as); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// Dialog attaches a stream to a dialog. The first request names the dialog;
+// from then on its events are streamed out, and every further request
+// carries a Command whose CommandResponse is streamed back. The stream ends
+// when the dialog does.
+func (g *grpcServer) Dialog(stream aripb.AriProxy_DialogServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	dialogID := req.GetDialogId()
+	pi, exists := proxyInstances.GetDialog(dialogID)
+	if !exists {
+		return status.Error(codes.NotFound, "unknown dialog")
+	}
+	if !allowed(stream.Context(), pi.application) {
+		return status.Error(codes.PermissionDenied, "application not allowed")
+	}
+
+	events := make(chan *aripb.Event, 64)
+	g.lock.Lock()
+	if g.dialogStreams[dialogID] == nil {
+		g.dialogStreams[dialogID] = make(map[chan *aripb.Event]bool)
+	}
+	g.dialogStreams[dialogID][events] = true
+	g.lock.Unlock()
+	defer func() {
+		g.lock.Lock()
+		delete(g.dialogStreams[dialogID], events)
+		if len(g.dialogStreams[dialogID]) == 0 {
+			delete(g.dialogStreams, dialogID)
+		}
+		g.lock.Unlock()
+	}()
+
+	// receive the commands in the background, as only this goroutine may
+	// send on the stream. Responses that arrive once the stream has ended
+	// are dropped.
+	responses := make(chan []byte, 64)
+	done := make(chan bool)
+	defer close(done)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			c := req.GetCommand()
+			if c == nil {
+				continue
+			}
+			command := ari.Command{UniqueID: c.UniqueId, URL: c.Url, Method: c.Method, Body: c.Body}
+			a := pi.admitCommand(command)
+			go func() {
+				response := make(chan []byte, 1)
+				pi.processCommand(context.Background(), command, response, a)
+				select {
+				case responses <- <-response:
+				case <-done:
+				}
+			}()
+		}
+	}()
+
+	for {
+		select {
+		case e := <-events:
+			if err := stream.Send(&aripb.DialogResponse{Response: &aripb.DialogResponse_Event{Event: e}}); err != nil {
+				return err
+			}
+		case response := <-responses:
+			var r ari.CommandResponse
+			ari.Unmarshal(response, &r)
+			cr := &aripb.CommandResponse{UniqueId: r.UniqueID, StatusCode: int32(r.StatusCode), ResponseBody: r.ResponseBody}
+			if err := stream.Send(&aripb.DialogResponse{Response: &aripb.DialogResponse_CommandResponse{CommandResponse: cr}}); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return err
+		case <-pi.quit:
+			return nil
+		}
+	}
+}
+
+// ListDialogs lists the active dialogs, optionally of a single application.
+func (g *grpcServer) ListDialogs(ctx context.Context, req *aripb.ListDialogsRequest) (*aripb.ListDialogsResponse, error) {
+	if !isAdmin(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "admin token required")
+	}
+	res := &aripb.ListDialogsResponse{}
+	for _, pi := range proxyInstances.Dialogs() {
+		if req.Application != "" && req.Application != pi.application {
+			continue
+		}
+		res.Dialogs = append(res.Dialogs, &aripb.DialogInfo{DialogId: pi.dialogID, Application: pi.application, Objects: pi.objects()})
+	}
+	return res, nil
+}
+
+// TerminateDialog hangs up a dialog's channels and tears it down.
+func (g *grpcServer) TerminateDialog(ctx context.Context, req *aripb.TerminateDialogRequest) (*aripb.TerminateDialogResponse, error) {
+	if !isAdmin(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "admin token required")
+	}
+	pi, exists := proxyInstances.GetDialog(req.DialogId)
+	if !exists {
+		return nil, status.Error(codes.NotFound, "unknown dialog")
+	}
+	pi.log().Info("terminating dialog on request of a gRPC client")
+	pi.terminate()
+	return &aripb.TerminateDialogResponse{}, nil
+}
+
+// publishAppStart sends an AppStart to every stream subscribed to its
+// application. Streams that cannot keep up miss the AppStart.
+func (g *grpcServer) publishAppStart(as ari.AppStart) {
+	m := &aripb.AppStart{Application: as.Application, DialogId: as.DialogID, ServerId: as.ServerID}
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	for c := range g.appStreams[as.Application] {
+		select {
+		case c <- m:
+		default:
+			logger.Warn("gRPC stream is too slow, dropping AppStart", ari.LogApplication, as.Application, ari.LogDialogID, as.DialogID)
+		}
+	}
+}
+
+// publishEvent sends an Event to every stream attached to its dialog.
+// Streams that cannot keep up miss the event.
+func (g *grpcServer) publishEvent(dialogID string, e *ari.Event) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if len(g.dialogStreams[dialogID]) == 0 {
+		return
+	}
+	m := &aripb.Event{ServerId: e.ServerID, Timestamp: timestamppb.New(e.Timestamp), Type: e.Type, AriBody: string(e.ARI_Body)}
+	for c := range g.dialogStreams[dialogID] {
+		select {
+		case c <- m:
+		default:
+			logger.Warn("gRPC stream is too slow, dropping event", ari.LogDialogID, dialogID, ari.LogEventType, e.Type)
+		}
+	}
+}
+
+// token returns the bearer token from the request metadata.
+func token(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if strings.HasPrefix(v, "Bearer ") {
+			return strings.TrimPrefix(v, "Bearer ")
+		}
+	}
+	return ""
+}
+
+// allowed reports whether the caller's token grants access to an
+// application.
+func allowed(ctx context.Context, application string) bool {
+	for _, app := range config.GRPC.Tokens[token(ctx)] {
+		if app == application {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdmin reports whether the caller holds an admin token.
+func isAdmin(ctx context.Context) bool {
+	t := token(ctx)
+	for _, admin := range config.GRPC.AdminTokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(admin)) == 1 {
+			return true
+		}
+	}
+	return false
+}
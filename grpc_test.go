@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nvisibleinc/go-ari-library"
+	"github.com/nvisibleinc/go-ari-proxy/aripb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// dialGRPC connects a client to the gRPC API of the tests, authenticated
+// with a token.
+func dialGRPC(t *testing.T, token string) (aripb.AriProxyClient, context.Context) {
+	t.Helper()
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return grpcLis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), 2*timeout)
+	t.Cleanup(cancel)
+	return aripb.NewAriProxyClient(conn), metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// waitStreams waits until the gRPC API has a stream subscribed to an
+// application and, when dialogID is set, attached to a dialog.
+func waitStreams(t *testing.T, app string, dialogID string) {
+	t.Helper()
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		grpcTap.lock.RLock()
+		ready := len(grpcTap.appStreams[app]) > 0 && (dialogID == "" || len(grpcTap.dialogStreams[dialogID]) > 0)
+		grpcTap.lock.RUnlock()
+		if ready {
+			return
+		}
+	}
+	t.Fatal("the gRPC stream was not attached")
+}
+
+func TestGRPCDialog(t *testing.T) {
+	client, ctx := dialGRPC(t, "token")
+	appStarts, err := client.SubscribeAppStarts(ctx, &aripb.SubscribeAppStartsRequest{Application: "grpc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStreams(t, "grpc", "")
+
+	id := fake.Call("grpc")
+	as, err := appStarts.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as.Application != "grpc" || as.ServerId != "test" {
+		t.Errorf("AppStart is %v, want application grpc of server test", as)
+	}
+	// the application on the bus starts as well, once the proxy set up the
+	// dialog a stream can attach to
+	waitInstance(t, "grpc")
+	for deadline := time.Now().Add(timeout); ; time.Sleep(10 * time.Millisecond) {
+		if _, ok := proxyInstances.GetDialog(as.DialogId); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the dialog was not set up")
+		}
+	}
+
+	stream, err := client.Dialog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = stream.Send(&aripb.DialogRequest{Request: &aripb.DialogRequest_DialogId{DialogId: as.DialogId}}); err != nil {
+		t.Fatal(err)
+	}
+	waitStreams(t, "grpc", as.DialogId)
+
+	// the bus compresses every message, the gRPC client gets them as they are
+	for _, c := range []*aripb.Command{
+		{UniqueId: "answer", Url: "/channels/" + id + "/answer", Method: "POST"},
+		{UniqueId: "get", Url: "/channels/" + id, Method: "GET"},
+	} {
+		if err = stream.Send(&aripb.DialogRequest{Request: &aripb.DialogRequest_Command{Command: c}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the responses and the event the answer causes may arrive in any order
+	responses := make(map[string]*aripb.CommandResponse)
+	var event *aripb.Event
+	for len(responses) < 2 || event == nil {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := res.GetCommandResponse(); r != nil {
+			responses[r.UniqueId] = r
+		}
+		if e := res.GetEvent(); e != nil && e.Type == "ChannelStateChange" {
+			event = e
+		}
+	}
+	if r := responses["answer"]; r.StatusCode != http.StatusNoContent {
+		t.Errorf("answering returned status %d, want 204", r.StatusCode)
+	}
+	var channel ari.Channel
+	if err = json.Unmarshal([]byte(responses["get"].ResponseBody), &channel); err != nil || channel.Id != id {
+		t.Errorf("getting the channel returned %q, want channel %s", responses["get"].ResponseBody, id)
+	}
+	var info eventInfo
+	if err = json.Unmarshal([]byte(event.AriBody), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Channel.ID != id {
+		t.Errorf("ChannelStateChange is about channel %q, want %q", info.Channel.ID, id)
+	}
+
+	fake.Hangup(id)
+	waitEnded(t, id)
+	for {
+		// the stream ends with the dialog
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+}
+
+func TestGRPCTerminateDialog(t *testing.T) {
+	id := fake.Call("grpc")
+	ai := waitInstance(t, "grpc")
+	waitEvent(t, ai, "StasisStart")
+	b, err := ai.BridgesCreate("mixing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pi, _ := proxyInstances.Get(id)
+	client, ctx := dialGRPC(t, "token")
+	if _, err = client.TerminateDialog(ctx, &aripb.TerminateDialogRequest{DialogId: pi.dialogID}); err == nil {
+		t.Error("terminating a dialog without an admin token succeeded")
+	}
+	before := len(fake.Requests())
+	admin, ctx := dialGRPC(t, "admin")
+	if _, err = admin.TerminateDialog(ctx, &aripb.TerminateDialogRequest{DialogId: pi.dialogID}); err != nil {
+		t.Fatal(err)
+	}
+	waitEnded(t, id)
+
+	// only the channel is hung up, the bridge is not a channel
+	var deleted []string
+	for _, r := range fake.Requests()[before:] {
+		if r.Method == "DELETE" {
+			deleted = append(deleted, r.Path)
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != "/channels/"+id {
+		t.Errorf("terminating deleted %v, want /channels/%s only", deleted, id)
+	}
+	if _, ok := fake.Channel(id); ok {
+		t.Error("channel still exists after terminating the dialog")
+	}
+	if _, ok := fake.Bridge(b.Id); !ok {
+		t.Error("the bridge was deleted")
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websockets tracks which applications' ARI websockets are connected.
+var websockets = struct {
+	sync.Mutex
+	connected map[string]bool
+}{connected: make(map[string]bool)}
+
+// readiness is the body of the /readyz response.
+type readiness struct {
+	Ready  bool                   `json:"ready"`
+	Checks map[string]checkResult `json:"checks"` // by dependency
+}
+
+// checkResult is the state of a dependency of the proxy.
+type checkResult struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// setConnected records whether the websocket of an application is connected.
+func setConnected(application string, connected bool) {
+	websockets.Lock()
+	defer websockets.Unlock()
+	websockets.connected[application] = connected
+}
+
+// serveHealth answers liveness probes. The proxy is alive as long as it
+// serves HTTP.
+func serveHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// serveReadiness answers readiness probes. The proxy is ready when the
+// websockets of all applications are connected, the message bus is up and
+// the ARI REST interface answers.
+func serveReadiness(w http.ResponseWriter, r *http.Request) {
+	rd := readiness{Ready: true, Checks: make(map[string]checkResult)}
+	check := func(name string, err error) {
+		if err != nil {
+			rd.Ready = false
+			rd.Checks[name] = checkResult{Error: err.Error()}
+			return
+		}
+		rd.Checks[name] = checkResult{OK: true}
+	}
+
+	websockets.Lock()
+	for _, app := range config.Applications {
+		var err error
+		if !websockets.connected[app] {
+			err = fmt.Errorf("websocket of application %s is not connected", app)
+		}
+		check(strings.Join([]string{"websocket", app}, "/"), err)
+	}
+	websockets.Unlock()
+	check("bus", checkBus(r.Context()))
+	check("ari", checkARI(r.Context()))
+
+	w.Header().Set("Content-Type", "application/json")
+	if !rd.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(rd)
+}
+
+// checkBus asks the message bus whether its connection is up, giving up
+// after a while so that a hanging broker does not hold up the probe.
+func checkBus(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return ari.BusHealthy(ctx)
+}
+
+// checkARI asks the ARI REST interface for the Asterisk system information.
+func checkARI(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	res, err := ariRequestContext(ctx, "GET", "/asterisk/info", "")
+	if ue, ok := err.(*url.Error); ok {
+		// the URL carries the ARI credentials
+		return ue.Err
+	}
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET /asterisk/info returned %s", res.Status)
+	}
+	return nil
+}
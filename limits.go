@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// appLimiters holds the limiter shared by the dialogs of each application.
+var appLimiters = struct {
+	sync.Mutex
+	m map[string]*limiter
+}{m: make(map[string]*limiter)}
+
+// errThrottled is returned for commands rejected by a limit.
+var errThrottled = errors.New("too many commands")
+
+// checkRateLimits validates the overflow actions of the limits of a
+// configuration.
+func checkRateLimits(limits map[string]rateLimitConfig) error {
+	for app, c := range limits {
+		for _, l := range []limitConfig{c.Application, c.Dialog} {
+			if l.Overflow != "" && l.Overflow != "reject" && l.Overflow != "queue" {
+				return fmt.Errorf("rate_limits: invalid overflow %q of application %s", l.Overflow, app)
+			}
+		}
+	}
+	return nil
+}
+
+// tokenBucket is a token bucket refilled at rate tokens per second up to
+// burst tokens.
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token, waiting up to max for it to become available. It
+// reports false, without taking a token, when none would be available in
+// time.
+func (b *tokenBucket) reserve(max time.Duration) bool {
+	b.lock.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		b.lock.Unlock()
+		return true
+	}
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	if wait > max {
+		b.lock.Unlock()
+		return false
+	}
+	// the token is spoken for, so later callers wait behind this one
+	b.tokens--
+	b.lock.Unlock()
+	time.Sleep(wait)
+	return true
+}
+
+// limiter enforces a rate and an in-flight limit on commands.
+type limiter struct {
+	application string
+	scope       string // dialog or application, for the throttling metrics
+	bucket      *tokenBucket
+	inFlight    chan struct{}
+	queue       bool
+	timeout     time.Duration
+}
+
+// newLimiter creates a limiter from its configuration, or returns nil when
+// the configuration sets no limit.
+func newLimiter(application string, scope string, c limitConfig) *limiter {
+	if c.Rate <= 0 && c.MaxInFlight <= 0 {
+		return nil
+	}
+	l := &limiter{application: application, scope: scope, queue: c.Overflow == "queue"}
+	if c.Rate > 0 {
+		burst := float64(c.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		l.bucket = &tokenBucket{rate: c.Rate, burst: burst, tokens: burst, last: time.Now()}
+	}
+	if c.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, c.MaxInFlight)
+	}
+	l.timeout = 5 * time.Second
+	if c.QueueTimeout > 0 {
+		l.timeout = time.Duration(c.QueueTimeout) * time.Millisecond
+	}
+	return l
+}
+
+// applicationLimiter returns the limiter shared by the dialogs of an
+// application, or nil.
+func applicationLimiter(application string) *limiter {
+	appLimiters.Lock()
+	defer appLimiters.Unlock()
+	l, ok := appLimiters.m[application]
+	if !ok {
+		l = newLimiter(application, "application", rateLimitsFor(application).Application)
+		appLimiters.m[application] = l
+	}
+	return l
+}
+
+// rateLimitsFor returns the rate limits of an application, falling back to
+// those of "*".
+func rateLimitsFor(application string) rateLimitConfig {
+	if c, ok := config.RateLimits[application]; ok {
+		return c
+	}
+	return config.RateLimits["*"]
+}
+
+// take takes a token of the limiter's rate, waiting up to the limiter's
+// timeout when the limiter queues. It reports whether the command had to
+// wait, and returns errThrottled when it is rejected.
+func (l *limiter) take() (bool, error) {
+	if l == nil || l.bucket == nil || l.bucket.reserve(0) {
+		return false, nil
+	}
+	if !l.queue || !l.bucket.reserve(l.timeout) {
+		l.count("rejected")
+		return false, errThrottled
+	}
+	return true, nil
+}
+
+// refund gives back the token of a command that another limit rejected, so
+// that it does not use up this limit's rate.
+func (l *limiter) refund() {
+	if l == nil || l.bucket == nil {
+		return
+	}
+	l.bucket.lock.Lock()
+	if l.bucket.tokens++; l.bucket.tokens > l.bucket.burst {
+		l.bucket.tokens = l.bucket.burst
+	}
+	l.bucket.lock.Unlock()
+}
+
+// enter takes an in-flight slot, waiting up to the limiter's timeout when
+// the limiter queues. It reports whether the command had to wait, and
+// returns errThrottled when it is rejected. Slots must be released.
+func (l *limiter) enter() (bool, error) {
+	if l == nil || l.inFlight == nil {
+		return false, nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return false, nil
+	default:
+	}
+	if l.queue {
+		select {
+		case l.inFlight <- struct{}{}:
+			return true, nil
+		case <-time.After(l.timeout):
+		}
+	}
+	l.count("rejected")
+	return false, errThrottled
+}
+
+// release frees the in-flight slot of an admitted command.
+func (l *limiter) release() {
+	if l != nil && l.inFlight != nil {
+		<-l.inFlight
+	}
+}
+
+// count records a throttled command.
+func (l *limiter) count(outcome string) {
+	commandsThrottled.WithLabelValues(l.application, l.scope, outcome).Inc()
+}
+
+// admit passes a command through the limits of its dialog and of its
+// application. The in-flight slots of both are taken before any token, and a
+// command rejected by one limit gives back what it took from the other, so
+// that it uses up neither. The returned function releases the command once
+// processed.
+func (p *proxyInstance) admit() (func(), error) {
+	app := applicationLimiter(p.application)
+	dialogQueued, err := p.limiter.enter()
+	if err != nil {
+		return nil, err
+	}
+	appQueued, err := app.enter()
+	if err != nil {
+		p.limiter.release()
+		return nil, err
+	}
+	release := func() {
+		app.release()
+		p.limiter.release()
+	}
+
+	queued, err := p.limiter.take()
+	if err != nil {
+		release()
+		return nil, err
+	}
+	dialogQueued = dialogQueued || queued
+	if queued, err = app.take(); err != nil {
+		p.limiter.refund()
+		release()
+		return nil, err
+	}
+	appQueued = appQueued || queued
+
+	if dialogQueued {
+		p.limiter.count("queued")
+	}
+	if appQueued {
+		app.count("queued")
+	}
+	return release, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newLimitedInstance returns a proxy instance of an application with the
+// given limits, which no other test uses, and a fresh application limiter.
+func newLimitedInstance(application string, limits rateLimitConfig) *proxyInstance {
+	if config.RateLimits == nil {
+		config.RateLimits = make(map[string]rateLimitConfig)
+	}
+	config.RateLimits[application] = limits
+	appLimiters.Lock()
+	delete(appLimiters.m, application)
+	appLimiters.Unlock()
+	return &proxyInstance{application: application, limiter: newLimiter(application, "dialog", limits.Dialog)}
+}
+
+func TestAdmitRefundsRejectedTokens(t *testing.T) {
+	p := newLimitedInstance("limits-rate", rateLimitConfig{
+		Application: limitConfig{Rate: 0.001, Burst: 1},
+		Dialog:      limitConfig{Rate: 0.001, Burst: 2},
+	})
+	release, err := p.admit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	// the application limit is used up, the dialog keeps its token
+	if _, err = p.admit(); err != errThrottled {
+		t.Fatalf("admit returned %v, want errThrottled", err)
+	}
+	if tokens := p.limiter.bucket.tokens; tokens < 1 {
+		t.Errorf("the dialog has %.2f tokens left, want 1", tokens)
+	}
+}
+
+func TestAdmitQueuesForInFlightSlots(t *testing.T) {
+	p := newLimitedInstance("limits-in-flight", rateLimitConfig{
+		Dialog: limitConfig{MaxInFlight: 1, Overflow: "queue", QueueTimeout: 50},
+	})
+	release, err := p.admit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err = p.admit(); err != errThrottled {
+		t.Fatalf("admit returned %v while the slot was taken, want errThrottled", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("admit gave up after %s, want the 50ms queue timeout", waited)
+	}
+
+	admitted := make(chan error)
+	go func() {
+		_, err := p.admit()
+		admitted <- err
+	}()
+	release()
+	if err = <-admitted; err != nil {
+		t.Errorf("a queued command was not admitted once the slot was released: %s", err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/nvisibleinc/go-ari-library"
+	"log/slog"
+	"os"
+)
+
+// configureLogging sets up the logger of the proxy and of the library from
+// the logging configuration.
+func configureLogging() error {
+	if config.Logging.Level != "" {
+		level, err := ari.ParseLevel(config.Logging.Level)
+		if err != nil {
+			return err
+		}
+		logLevel.Set(level)
+	}
+	l, err := ari.NewLogger(os.Stdout, config.Logging.Format, logLevel)
+	if err != nil {
+		return err
+	}
+	logger = l
+	ari.SetLogger(logger)
+	return nil
+}
+
+// fatal logs an error and exits.
+func fatal(msg string, args ...interface{}) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+// log returns the logger of the proxy instance, which adds the application
+// and dialog ID to every record.
+func (p *proxyInstance) log() *slog.Logger {
+	return logger.With(ari.LogApplication, p.application, ari.LogDialogID, p.dialogID)
+}
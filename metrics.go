@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/nvisibleinc/go-ari-library"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+// Prometheus metrics of the proxy.
+var (
+	activeDialogs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ari_proxy_active_dialogs",
+		Help: "Dialogs currently active, by application.",
+	}, []string{"application"})
+	appStarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_app_starts_total",
+		Help: "AppStarts published, by application.",
+	}, []string{"application"})
+	eventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_events_received_total",
+		Help: "Events received from the ARI websockets, by type.",
+	}, []string{"type"})
+	eventsRouted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_events_routed_total",
+		Help: "Events published to a dialog, by type.",
+	}, []string{"type"})
+	eventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_events_dropped_total",
+		Help: "Events no dialog received, by type.",
+	}, []string{"type"})
+	commandsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_commands_total",
+		Help: "Commands processed, by method and response status code.",
+	}, []string{"method", "status"})
+	commandsThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_commands_throttled_total",
+		Help: "Commands queued or rejected by a rate limit, by application, limit scope and outcome.",
+	}, []string{"application", "scope", "outcome"})
+	ariRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ari_proxy_ari_request_duration_seconds",
+		Help:    "Latency of the requests to the ARI REST interface, by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+	websocketReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_websocket_reconnects_total",
+		Help: "Reconnections of the ARI websockets, by application.",
+	}, []string{"application"})
+	busPublishErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ari_proxy_bus_publish_errors_total",
+		Help: "Messages the message bus failed to publish, by kind of topic.",
+	}, []string{"topic"})
+)
+
+func init() {
+	prometheus.MustRegister(activeDialogs, appStarts, eventsReceived, eventsRouted, eventsDropped,
+		commandsProcessed, commandsThrottled, ariRequestDuration, websocketReconnects, busPublishErrors)
+}
+
+// runMonitoring serves the metrics and the health probes until the listener
+// fails.
+func runMonitoring() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", serveHealth)
+	mux.HandleFunc("/readyz", serveReadiness)
+	logger.Info("starting monitoring endpoint", "listen", config.Monitoring.Listen)
+	err := http.ListenAndServe(config.Monitoring.Listen, mux)
+	fatal("unable to serve the monitoring endpoint", ari.LogError, err)
+}
+
+// countPublishError counts a message the bus failed to publish. Dialog
+// topics are counted by their kind, so that the dialog IDs do not end up in
+// the labels, and the signalling topics of the applications as "app_start".
+func countPublishError(topic string, err error) {
+	kind, ok := ari.DialogTopicKind(topic)
+	if !ok {
+		kind = "app_start"
+	}
+	busPublishErrors.WithLabelValues(kind).Inc()
+}
+
+// methodLabel returns the label of a request method. The methods are the
+// ones applications send, so any besides those of the ARI REST interface
+// are counted as "other" to keep the number of labels bounded.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+		return method
+	}
+	return "other"
+}
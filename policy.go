@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"net/url"
+	"strings"
+)
+
+// objectCollections are the ARI resources whose path segment after the
+// collection name is the ID of an object owned by a dialog.
+var objectCollections = map[string]bool{"channels": true, "bridges": true, "playbacks": true}
+
+// checkPolicy validates the actions of the policies of a configuration.
+func checkPolicy(policies map[string]policyConfig) error {
+	for app, policy := range policies {
+		if policy.Default != "" && policy.Default != "allow" && policy.Default != "deny" {
+			return fmt.Errorf("policy: invalid default %q of application %s", policy.Default, app)
+		}
+		for _, r := range policy.Rules {
+			if r.Action != "allow" && r.Action != "deny" {
+				return fmt.Errorf("policy: invalid action %q of application %s", r.Action, app)
+			}
+		}
+	}
+	return nil
+}
+
+// authorize checks a Command against the policy of the proxy instance's
+// application and, if the policy asks for it, against the objects the
+// dialog owns. Paths with "." or ".." segments are always refused, as they
+// would reach another resource than the one the policy matched.
+func (p *proxyInstance) authorize(c ari.Command) error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return err
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("%s %s: dot segments are not allowed", c.Method, u.Path)
+		}
+	}
+	policy, ok := config.Policy[p.application]
+	if !ok {
+		policy, ok = config.Policy["*"]
+	}
+	if !ok {
+		return nil
+	}
+	if !policy.allows(c.Method, u.Path) {
+		return fmt.Errorf("%s %s is not allowed", c.Method, u.Path)
+	}
+	if policy.CheckOwnership {
+		return p.checkOwnership(c.Method, u)
+	}
+	return nil
+}
+
+// allows reports whether the first rule matching a method and path allows
+// it, falling back to the default action.
+func (pc policyConfig) allows(method string, path string) bool {
+	for _, r := range pc.Rules {
+		if r.matches(method, path) {
+			return r.Action == "allow"
+		}
+	}
+	return pc.Default != "deny"
+}
+
+// matches reports whether a rule applies to a method and path.
+func (r policyRule) matches(method string, path string) bool {
+	if len(r.Methods) > 0 {
+		found := false
+		for _, m := range r.Methods {
+			if strings.EqualFold(m, method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return matchPath(r.Path, path)
+}
+
+// matchPath matches a path against a pattern segment by segment. A "*"
+// segment matches any single segment, and a final "**" segment matches the
+// rest of the path.
+func matchPath(pattern string, path string) bool {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	s := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range ps {
+		if p == "**" && i == len(ps)-1 {
+			return true
+		}
+		if i >= len(s) || (p != "*" && p != s[i]) {
+			return false
+		}
+	}
+	return len(ps) == len(s)
+}
+
+// checkOwnership verifies that a command only acts on ARI objects owned by
+// the dialog. The object named in the path must be owned by the dialog,
+// unless the command creates it under an ID no other dialog owns, and every
+// channel passed in the "channel" query parameter must be owned as well.
+func (p *proxyInstance) checkOwnership(method string, u *url.URL) error {
+	s := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var id string
+	switch {
+	case len(s) >= 2 && objectCollections[s[0]]:
+		id = s[1]
+	case len(s) >= 3 && s[0] == "recordings" && s[1] == "live":
+		id = s[2]
+	}
+	if id != "" && !p.owns(id) {
+		owner, exists := proxyInstances.Get(id)
+		creates := method == "POST" && len(s) == 2 && s[0] != "playbacks"
+		if !creates || (exists && owner != p) {
+			return fmt.Errorf("%s is not owned by dialog %s", id, p.dialogID)
+		}
+	}
+	for _, channels := range u.Query()["channel"] {
+		for _, channel := range strings.Split(channels, ",") {
+			if !p.owns(channel) {
+				return fmt.Errorf("%s is not owned by dialog %s", channel, p.dialogID)
+			}
+		}
+	}
+	return nil
+}
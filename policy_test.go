@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/nvisibleinc/go-ari-library"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	defer func(policy map[string]policyConfig) { config.Policy = policy }(config.Policy)
+	config.Policy = map[string]policyConfig{
+		"policy": {
+			Default: "deny",
+			Rules: []policyRule{
+				{Action: "deny", Methods: []string{"DELETE"}, Path: "/channels/*"},
+				{Action: "allow", Path: "/channels/**"},
+			},
+			CheckOwnership: true,
+		},
+	}
+	p := &proxyInstance{dialogID: "dialog", application: "policy", channelObjects: make(map[string]bool)}
+	p.ariObjects = []string{"owned"}
+
+	for _, test := range []struct {
+		method  string
+		url     string
+		allowed bool
+	}{
+		{"POST", "/channels/owned/answer", true},
+		{"GET", "/channels/owned?channel=owned", true},
+		{"DELETE", "/channels/owned", false},
+		{"POST", "/channels/other/answer", false},
+		{"GET", "/deviceStates/foo", false},
+		{"GET", "/channels/owned/../../deviceStates/foo", false},
+		{"GET", "/channels/owned/%2e%2e/%2e%2e/deviceStates/foo", false},
+		{"PUT", "/channels/owned/./../../deviceStates/foo", false},
+		{"POST", "/channels/owned/..", false},
+		{"POST", "/channels/owned/play?media=sound:a..b", true},
+	} {
+		err := p.authorize(ari.Command{Method: test.method, URL: test.url})
+		if (err == nil) != test.allowed {
+			t.Errorf("%s %s: authorize returned %v, want allowed %v", test.method, test.url, err, test.allowed)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"github.com/nvisibleinc/go-ari-library"
 	"strings"
 	"sync"
+	"time"
 )
 
 // proxyInstanceMap is a singleton which holds the map
@@ -25,15 +27,176 @@ func NewproxyInstanceMap() *proxyInstanceMap {
 // The Config struct contains the information the was unmarshaled from the
 // configuration file for ths proxy.
 type Config struct {
-	Origin       string      `json:"origin"`        // connection to ARI events
-	ServerID     string      `json:"server_id"`     // unique server ident
-	Applications []string    `json:"applications"`  // slice of applications to listen for
-	WebsocketURL string      `json:"websocket_url"` // websocket to connect to
-	StasisURL    string      `json:"stasis_url"`    // Base URL of ARI REST API
-	WSUser       string      `json:"ws_user"`       // username of websocket connection
-	WSPassword   string      `json:"ws_password"`   // pass of websocket connection
-	MessageBus   string      `json:"message_bus"`   // type of message bus to publish to
-	BusConfig    interface{} `json:"bus_config"`    // configuration of the message bus we're publishing to
+	Origin       string                     `json:"origin"`        // connection to ARI events
+	ServerID     string                     `json:"server_id"`     // unique server ident
+	Applications []string                   `json:"applications"`  // slice of applications to listen for
+	WebsocketURL string                     `json:"websocket_url"` // websocket to connect to
+	StasisURL    string                     `json:"stasis_url"`    // Base URL of ARI REST API
+	WSUser       string                     `json:"ws_user"`       // username of websocket connection
+	WSPassword   string                     `json:"ws_password"`   // pass of websocket connection
+	MessageBus   string                     `json:"message_bus"`   // type of message bus to publish to
+	BusConfig    interface{}                `json:"bus_config"`    // configuration of the message bus we're publishing to
+	Encoding     string                     `json:"encoding"`      // wire encoding of bus messages, json by default
+	EventVersion int                        `json:"event_version"` // Event envelope version to publish, the latest by default
+	Compression  compressionConfig          `json:"compression"`   // compression of large bus payloads
+	Security     map[string]securityConfig  `json:"security"`      // keys by application
+	Policy       map[string]policyConfig    `json:"policy"`        // command policy by application, "*" for the others
+	RateLimits   map[string]rateLimitConfig `json:"rate_limits"`   // command limits by application, "*" for the others
+	Admission    admissionConfig            `json:"admission"`     // limits on concurrent dialogs
+	Gateway      gatewayConfig              `json:"gateway"`       // websocket gateway for clients without a bus
+	GRPC         grpcConfig                 `json:"grpc"`          // gRPC API for clients without a bus
+	Monitoring   monitoringConfig           `json:"monitoring"`    // metrics and health endpoint
+	Admin        adminConfig                `json:"admin"`         // operator API to inspect and manage dialogs
+	Logging      loggingConfig              `json:"logging"`       // level and format of the logs
+	Tracing      tracingConfig              `json:"tracing"`       // export of OpenTelemetry spans
+	Audit        auditConfig                `json:"audit"`         // audit trail and CDRs of the dialogs
+}
+
+// compressionConfig holds the compression of the ARI bodies of Events and the
+// bodies of CommandResponses on the bus. Bodies are only compressed when
+// Algorithm is set.
+type compressionConfig struct {
+	Algorithm string `json:"algorithm"` // gzip or zstd
+	Threshold int    `json:"threshold"` // smallest body to compress, in bytes
+	MaxSize   int    `json:"max_size"`  // largest body to decompress, in bytes
+}
+
+// securityConfig holds the keys of an application. Commands must be signed
+// with one of the HMAC or Ed25519 keys as soon as any is set, and Events are
+// encrypted with the AES key named by EncryptionKeyID, if any. Binary keys are
+// base64 encoded.
+type securityConfig struct {
+	HMACKeys          map[string]string `json:"hmac_keys"`           // key ID to shared secret
+	Ed25519PublicKeys map[string]string `json:"ed25519_public_keys"` // key ID to verification key
+	AESKeys           map[string]string `json:"aes_keys"`            // key ID to 16, 24 or 32 byte AES key
+	EncryptionKeyID   string            `json:"encryption_key_id"`   // key to encrypt Events with
+}
+
+// policyConfig holds the command policy of an application. The first rule
+// matching a command decides, and commands no rule matches are handled by
+// Default. CheckOwnership additionally restricts a dialog to its own ARI
+// objects.
+type policyConfig struct {
+	Default        string       `json:"default"`         // allow or deny, allow by default
+	Rules          []policyRule `json:"rules"`           // rules in order of precedence
+	CheckOwnership bool         `json:"check_ownership"` // refuse commands on objects of other dialogs
+}
+
+// policyRule allows or denies the commands matching its methods and path
+// pattern.
+type policyRule struct {
+	Action  string   `json:"action"`  // allow or deny
+	Methods []string `json:"methods"` // HTTP methods, any when empty
+	Path    string   `json:"path"`    // path pattern, see matchPath
+}
+
+// rateLimitConfig holds the command limits of an application. The
+// Application limits are shared by all of its dialogs, the Dialog limits
+// apply to each dialog separately.
+type rateLimitConfig struct {
+	Application limitConfig `json:"application"`
+	Dialog      limitConfig `json:"dialog"`
+}
+
+// limitConfig holds a token bucket rate limit and a cap on the commands in
+// flight. Zero values disable a limit. Commands over a limit are rejected,
+// or queued for up to QueueTimeout when Overflow is "queue".
+type limitConfig struct {
+	Rate         float64 `json:"rate"`             // commands per second
+	Burst        int     `json:"burst"`            // bucket size, at least 1
+	MaxInFlight  int     `json:"max_in_flight"`    // commands sent to ARI at a time
+	Overflow     string  `json:"overflow"`         // reject or queue, reject by default
+	QueueTimeout int     `json:"queue_timeout_ms"` // longest wait in the queue, 5000 by default
+}
+
+// admissionConfig holds the limits on concurrent dialogs. Zero values
+// disable a limit. Channels entering Stasis over a limit get no dialog and
+// are handled by the Overload action instead.
+type admissionConfig struct {
+	MaxDialogs   int            `json:"max_dialogs"`  // dialogs across all applications
+	Applications map[string]int `json:"applications"` // dialogs by application
+	Overload     overloadConfig `json:"overload"`
+}
+
+// overloadConfig holds the action taken on channels refused a dialog:
+// "continue" in the dialplan at Context, Extension and Priority, "hangup"
+// with Cause, or "busy", which plays Media for BusySeconds before hanging up.
+type overloadConfig struct {
+	Action      string `json:"action"`       // continue, hangup or busy, hangup by default
+	Context     string `json:"context"`      // dialplan context to continue in
+	Extension   string `json:"extension"`    // dialplan extension, the current one by default
+	Priority    int    `json:"priority"`     // dialplan priority, the current one by default
+	Cause       int    `json:"cause"`        // hangup cause code, 34 by default
+	Media       string `json:"media"`        // busy media URI, tone:busy by default
+	BusySeconds int    `json:"busy_seconds"` // how long to play the busy media, 5 by default
+}
+
+// gatewayConfig holds the configuration of the websocket gateway. The gateway
+// is only started when Listen is set. Tokens maps each access token to the
+// applications its holder may subscribe to and send commands for.
+type gatewayConfig struct {
+	Listen string              `json:"listen"` // address to serve the gateway on
+	Path   string              `json:"path"`   // URL path of the websocket endpoint
+	Tokens map[string][]string `json:"tokens"` // access token to allowed applications
+}
+
+// grpcConfig holds the configuration of the gRPC API. The API is only served
+// when Listen is set. Tokens work as for the gateway; AdminTokens may
+// additionally list and terminate dialogs.
+type grpcConfig struct {
+	Listen      string              `json:"listen"`       // address to serve the API on
+	Tokens      map[string][]string `json:"tokens"`       // access token to allowed applications
+	AdminTokens []string            `json:"admin_tokens"` // access tokens for the admin calls
+}
+
+// monitoringConfig holds the configuration of the monitoring endpoint, which
+// serves the metrics and health probes only when Listen is set.
+type monitoringConfig struct {
+	Listen string `json:"listen"` // address to serve /metrics, /healthz and /readyz on
+}
+
+// adminConfig holds the configuration of the admin API, which is only served
+// when Listen is set. Callers authenticate with one of Tokens as a bearer
+// token.
+type adminConfig struct {
+	Listen       string   `json:"listen"`        // address to serve the API on
+	Tokens       []string `json:"tokens"`        // access tokens
+	RecentEvents int      `json:"recent_events"` // events kept per dialog, 20 by default
+}
+
+// loggingConfig holds the level and format of the logs. The level can be
+// changed at runtime through the admin API.
+type loggingConfig struct {
+	Level  string `json:"level"`  // debug, info, warn or error, info by default
+	Format string `json:"format"` // text or json, text by default
+}
+
+// tracingConfig holds the export of the spans of events and commands. Spans
+// are only exported when Exporter is set.
+type tracingConfig struct {
+	Exporter    string   `json:"exporter"`     // otlp, stdout or file
+	Endpoint    string   `json:"endpoint"`     // OTLP gRPC collector address, localhost:4317 by default
+	Insecure    bool     `json:"insecure"`     // connect to the collector without TLS
+	File        string   `json:"file"`         // file the file exporter appends to
+	ServiceName string   `json:"service_name"` // go-ari-proxy by default
+	SampleRatio *float64 `json:"sample_ratio"` // share of new traces sampled, 1 by default
+}
+
+// auditConfig holds the destination of the audit trail and CDRs of the
+// dialogs, which are only written when Output is set.
+type auditConfig struct {
+	Output     string `json:"output"`      // file or bus
+	File       string `json:"file"`        // JSONL file of the file output
+	MaxSizeMB  int    `json:"max_size_mb"` // size the file is rotated at, 100 by default
+	MaxBackups int    `json:"max_backups"` // rotated files kept
+	Topic      string `json:"topic"`       // bus topic of the bus output, audit by default
+}
+
+// eventTap is implemented by the frontends which receive AppStarts and dialog
+// events alongside the message bus.
+type eventTap interface {
+	publishAppStart(as ari.AppStart)
+	publishEvent(dialogID string, e *ari.Event)
 }
 
 // proxyInstance struct contains the channels necessary for communications
@@ -41,19 +204,51 @@ type Config struct {
 // primarily used as the communications bus for setting up new instances of
 // applications.
 type proxyInstance struct {
-	commandChannel  chan []byte
-	responseChannel chan []byte
-	Events          chan []byte
-	quit            chan int
-	ariObjects      []string
+	dialogID         string
+	application      string
+	commandChannel   chan []byte
+	responseChannel  chan []byte
+	Events           chan []byte
+	quit             chan int
+	shutDownOnce     sync.Once // closes quit and tears the dialog down once
+	ariObjects       []string
+	channelObjects   map[string]bool // owned objects that are channels
+	objectLock       sync.RWMutex    // guards ariObjects and channelObjects
+	limiter          *limiter        // command limits of the dialog, nil when unlimited
+	queue            []func()        // work on the dialog's events, run in order by runQueue
+	queueClosed      bool            // set once the dialog is torn down
+	queueLock        sync.Mutex      // guards queue and queueClosed
+	queueReady       chan bool       // signalled when work is queued or the queue closes
+	eventLock        sync.Mutex      // guards eventSequence
+	eventSequence    uint64
+	responseLock     sync.Mutex // orders the numbering and sending of responses
+	responseSequence uint64     // updated atomically, so it can be read without responseLock
+	started          time.Time
+	recentLock       sync.Mutex  // guards recent
+	recent           []ari.Event // last events of the dialog, for the admin API
+	statsLock        sync.Mutex  // guards the statistics of the dialog's CDR below
+	channels         []string    // channels the dialog's events were about
+	events           int
+	commands         int
+	commandsFailed   int
 }
 
 // NewProxyInstance initializes a new proxy instance.
-func NewProxyInstance(dialogID string) *proxyInstance {
+func NewProxyInstance(dialogID string, application string) *proxyInstance {
 	var p proxyInstance
+	p.dialogID = dialogID
+	p.application = application
+	p.started = time.Now()
 	p.quit = make(chan int)
+	p.channelObjects = make(map[string]bool)
+	p.queueReady = make(chan bool, 1)
+	p.limiter = newLimiter(application, "dialog", rateLimitsFor(application).Dialog)
 	p.Events = ari.InitProducer(strings.Join([]string{"events", dialogID}, "_"))
+	// the taps may send commands as soon as the dialog exists, and
+	// sendResponse tells their responses apart by this producer
+	p.responseChannel = ari.InitProducer(strings.Join([]string{"responses", dialogID}, "_"))
 	go p.runCommandConsumer(dialogID)
+	go p.runQueue()
 	return &p
 }
 
@@ -89,8 +284,10 @@ type minChan struct {
 	ID string `json:"id"`
 }
 
-// ID struct is used to get the ID or name of objects returned from ARI REST calls.
+// ID struct is used to get the ID or name of objects returned from ARI REST
+// calls. Only channels have a dialplan location.
 type ID struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Dialplan json.RawMessage `json:"dialplan"`
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+)
+
+// loadKeys decodes the keys of the applications from their security
+// configuration.
+func loadKeys(security map[string]securityConfig) (map[string]*ari.Keys, error) {
+	keys := make(map[string]*ari.Keys)
+	for app, s := range security {
+		k := &ari.Keys{
+			HMACKeys:          make(map[string][]byte),
+			Ed25519PublicKeys: make(map[string]ed25519.PublicKey),
+			EncryptionKeyID:   s.EncryptionKeyID,
+			AESKeys:           make(map[string][]byte),
+		}
+		for id, secret := range s.HMACKeys {
+			k.HMACKeys[id] = []byte(secret)
+		}
+		for id, encoded := range s.Ed25519PublicKeys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil || len(key) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("security: invalid ed25519 public key %q of application %s", id, app)
+			}
+			k.Ed25519PublicKeys[id] = ed25519.PublicKey(key)
+		}
+		for id, encoded := range s.AESKeys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err == nil {
+				_, err = aes.NewCipher(key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("security: invalid AES key %q of application %s", id, app)
+			}
+			k.AESKeys[id] = key
+		}
+		if _, ok := k.AESKeys[k.EncryptionKeyID]; k.EncryptionKeyID != "" && !ok {
+			return nil, fmt.Errorf("security: unknown encryption key %q of application %s", k.EncryptionKeyID, app)
+		}
+		keys[app] = k
+	}
+	return keys, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/nvisibleinc/go-ari-library"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+)
+
+// tracer creates the spans of the proxy. It does nothing until tracing is
+// configured.
+var tracer = otel.Tracer("github.com/nvisibleinc/go-ari-proxy")
+
+// shutdownTracing flushes and stops the exporter of the spans.
+var shutdownTracing = func(context.Context) error { return nil }
+
+// initTracing sets up the exporter, sampler and propagators of the spans
+// from the tracing configuration.
+func initTracing() error {
+	c := config.Tracing
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch c.Exporter {
+	case "":
+		return nil
+	case "otlp":
+		opts := []otlptracegrpc.Option{}
+		if c.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(c.Endpoint))
+		}
+		if c.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(context.Background(), opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "file":
+		var f *os.File
+		if f, err = os.OpenFile(c.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+		}
+	default:
+		return fmt.Errorf("tracing: unknown exporter %q", c.Exporter)
+	}
+	if err != nil {
+		return fmt.Errorf("tracing: %s", err)
+	}
+
+	name := c.ServiceName
+	if name == "" {
+		name = "go-ari-proxy"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(name), semconv.ServiceInstanceID(proxyInstanceID)))
+	if err != nil {
+		return fmt.Errorf("tracing: %s", err)
+	}
+	ratio := 1.0
+	if c.SampleRatio != nil {
+		ratio = *c.SampleRatio
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	shutdownTracing = provider.Shutdown
+	return nil
+}
+
+// traceContext returns the trace context of the span in ctx, for the
+// envelope of a message, or nil when there is no span to propagate.
+func traceContext(ctx context.Context) ari.TraceContext {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	t := make(ari.TraceContext)
+	otel.GetTextMapPropagator().Inject(ctx, t)
+	return t
+}